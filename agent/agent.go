@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/NatoBoram/BlackCompany/micro"
+	"github.com/NatoBoram/BlackCompany/record"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+// Strategist chooses the strategy to play once the game has started and the
+// enemy's race is known.
+type Strategist func(b *bot.Bot) *bot.Strategy
+
+// Fixed is a strategist that always chooses the provided strategy.
+func Fixed(strategy *bot.Strategy) Strategist {
+	return func(b *bot.Bot) *bot.Strategy {
+		return strategy
+	}
+}
+
+// Run initializes the bot then plays the chosen strategy until the game is
+// over. When a recorder is provided, everything the bot sees and does is saved
+// in it. It returns what happened during the game.
+func Run(b *bot.Bot, strategist Strategist, recorder *record.Writer) *Game {
+	stop := make(chan struct{})
+	b.Init(stop)
+	b.Observe()
+	b.InitState()
+	b.DetectEnemyRace()
+
+	strategy := strategist(b)
+	log.Info("Playing %s against %v", strategy.Name, b.EnemyRace)
+
+	game := newGame(b, strategy)
+	recordGame(b, recorder)
+	recordObservation(b, recorder)
+
+	var lastStep string
+	for b.Client.Status == api.Status_in_game {
+		b.Step()
+		lastStep = macro.Step(b, strategy, lastStep, game.Trace)
+		micro.Step(b)
+
+		// Once a step is done, send it to the game
+		b.Cmds.Process(&b.Actions)
+		if len(b.Actions) > 0 {
+			recordActions(b, recorder)
+
+			if _, err := b.Client.Action(api.RequestAction{Actions: b.Actions}); err != nil {
+				log.Warn("Failed to send actions: %v", err)
+			}
+
+			b.Actions = nil
+		}
+
+		// The game advances by itself in real-time
+		if !b.Client.Realtime {
+			step := api.RequestStep{Count: uint32(b.FramesPerOrder)}
+			if _, err := b.Client.Step(step); err != nil {
+				if err.Error() == "Not in a game" {
+					break
+				}
+
+				log.Error("An unknown error occurred while stepping: %v", err)
+				break
+			}
+		}
+
+		b.Observe()
+		game.observe(b)
+		recordObservation(b, recorder)
+	}
+
+	if game.Over() {
+		log.Info("%v with %s against %v on %s", game.Result, game.Strategy, game.EnemyRace, game.Map)
+	}
+
+	stop <- struct{}{}
+	return game
+}
+
+// recordGame saves the game info and data in the recording.
+func recordGame(b *bot.Bot, recorder *record.Writer) {
+	if recorder == nil {
+		return
+	}
+
+	if err := recorder.WriteGameInfo(b.Info); err != nil {
+		log.Warn("Failed to record game info: %v", err)
+	}
+
+	if err := recorder.WriteData(b.Data); err != nil {
+		log.Warn("Failed to record game data: %v", err)
+	}
+}
+
+// recordObservation saves the latest observation in the recording.
+func recordObservation(b *bot.Bot, recorder *record.Writer) {
+	if recorder == nil || b.Obs == nil {
+		return
+	}
+
+	observation := &api.ResponseObservation{
+		Observation:  b.Obs,
+		Chat:         b.Chat,
+		PlayerResult: b.Result,
+		ActionErrors: b.Errors,
+	}
+
+	if err := recorder.WriteObservation(observation); err != nil {
+		log.Warn("Failed to record observation: %v", err)
+	}
+}
+
+// recordActions saves the actions that are about to be sent in the recording.
+func recordActions(b *bot.Bot, recorder *record.Writer) {
+	if recorder == nil {
+		return
+	}
+
+	if err := recorder.WriteActions(b.Actions); err != nil {
+		log.Warn("Failed to record actions: %v", err)
+	}
+}
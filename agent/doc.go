@@ -0,0 +1,2 @@
+// agent runs the bot's main loop against a game.
+package agent
@@ -0,0 +1,76 @@
+package agent
+
+import (
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+// Game is what happened in a game played by the bot.
+type Game struct {
+	// Map is the name of the map.
+	Map string
+
+	// Strategy is the name of the strategy that was played.
+	Strategy string
+
+	// EnemyRace is the enemy's race, as detected during the game.
+	EnemyRace api.Race
+
+	// Result is whether the bot won or lost. It's undecided when the game
+	// stopped without a result.
+	Result api.Result
+
+	// Loops is the length of the game in game loops.
+	Loops int
+
+	// PeakSupply is the highest supply the bot reached.
+	PeakSupply int
+
+	// Score is the bot's latest score.
+	Score *api.Score
+
+	// Trace is the timing of the build order.
+	Trace *macro.Trace
+}
+
+// newGame starts keeping track of a game.
+func newGame(b *bot.Bot, strategy *bot.Strategy) *Game {
+	game := &Game{
+		Map:      b.Info.GetMapName(),
+		Strategy: strategy.Name,
+		Result:   api.Result_Undecided,
+		Trace:    macro.NewTrace(strategy),
+	}
+
+	game.observe(b)
+	return game
+}
+
+// observe updates the game with the latest observation.
+func (g *Game) observe(b *bot.Bot) {
+	g.EnemyRace = b.EnemyRace
+
+	if b.Obs != nil {
+		g.Loops = max(g.Loops, int(b.Obs.GameLoop))
+
+		if common := b.Obs.PlayerCommon; common != nil {
+			g.PeakSupply = max(g.PeakSupply, int(common.FoodUsed))
+		}
+
+		if b.Obs.Score != nil {
+			g.Score = b.Obs.Score
+		}
+	}
+
+	for _, result := range b.Result {
+		if b.Obs != nil && b.Obs.PlayerCommon != nil && result.PlayerId == b.Obs.PlayerCommon.PlayerId {
+			g.Result = result.Result
+		}
+	}
+}
+
+// Over tells if the game ended with a result.
+func (g *Game) Over() bool {
+	return g.Result != api.Result_Undecided
+}
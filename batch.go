@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/NatoBoram/BlackCompany/history"
+	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/client"
+)
+
+// batchRaces are the races of the built-in AI in a batch of games.
+var batchRaces = []api.Race{api.Race_Terran, api.Race_Zerg, api.Race_Protoss}
+
+// batchDifficulties are the difficulties of the built-in AI in a batch of
+// games.
+var batchDifficulties = []api.Difficulty{
+	api.Difficulty_Medium,
+	api.Difficulty_Hard,
+	api.Difficulty_Harder,
+	api.Difficulty_VeryHard,
+	api.Difficulty_CheatVision,
+}
+
+// interfaceOptions are what the bot receives from the game, like s2l's
+// defaults.
+var interfaceOptions = &api.InterfaceOptions{
+	Raw:                 true,
+	Score:               true,
+	ShowBurrowedShadows: true,
+	ShowCloaked:         true,
+}
+
+// matchup is the map and the opponent of a game in a batch.
+type matchup struct {
+	Map        string
+	Race       api.Race
+	Difficulty api.Difficulty
+}
+
+// matchups interleaves the races, the difficulties and the maps so that each
+// game changes all three, and every combination is played once before any is
+// repeated. The numbers of races and difficulties have no common divisor, so
+// they go through every pair together, and the maps shift by one after each
+// round of pairs.
+func matchups(games int) []matchup {
+	maps := slices.Concat(maps2024Season2, maps2024Season4)
+	pairs := len(batchRaces) * len(batchDifficulties)
+
+	matchups := make([]matchup, 0, games)
+	for i := range games {
+		matchups = append(matchups, matchup{
+			Race:       batchRaces[i%len(batchRaces)],
+			Difficulty: batchDifficulties[i%len(batchDifficulties)],
+			Map:        maps[(i%pairs+i/pairs)%len(maps)],
+		})
+	}
+
+	return matchups
+}
+
+// runBatch plays many games in a row against the built-in AI in the same
+// StarCraft II process, then prints the win rates.
+func runBatch(env *Env, flags Flags) {
+	c, err := connect(env, flags)
+	if err != nil {
+		log.Fatal("failed to launch the game: %v", err)
+	}
+
+	matches := make([]history.Match, 0, flags.Games)
+	for i, m := range matchups(flags.Games) {
+		log.Info("Game %d of %d: %v %v on %s", i+1, flags.Games, m.Difficulty, m.Race, m.Map)
+
+		bot := client.NewParticipant(api.Race_Terran, "BlackCompany")
+		cpu := client.NewComputer(m.Race, m.Difficulty, api.AIBuild_RandomBuild)
+		if err := startGame(c, m.Map, flags.Realtime, bot, cpu); err != nil {
+			log.Error("Failed to start game %d: %v", i+1, err)
+			continue
+		}
+
+		game := runAgent(c, numberedFlags(flags, i+1))
+		saveTrace(game.Trace, numbered(flags.Trace, i+1))
+		match := history.NewMatch(game, m.Difficulty)
+		saveMatch(game, match, flags.History)
+
+		if !game.Over() {
+			log.Warn("Game %d ended without a result", i+1)
+			continue
+		}
+
+		matches = append(matches, match)
+	}
+
+	printWinRates(matches)
+}
+
+// connect launches StarCraft II and connects to it without starting a game.
+func connect(env *Env, flags Flags) (*client.Client, error) {
+	if env.PROTON_PATH == "" || env.STEAM_COMPAT_DATA_PATH == "" {
+		client.LaunchPortStart = flags.Port
+		config := client.NewGameConfig(client.NewParticipant(api.Race_Terran, "BlackCompany"))
+		config.LaunchStarcraft()
+		return config.Client, nil
+	}
+
+	paths, err := sc2Paths(env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get StarCraft II paths: %w", err)
+	}
+
+	if err = launchProton(paths, flags); err != nil {
+		return nil, fmt.Errorf("failed to launch StarCraft II using Proton: %w", err)
+	}
+
+	c := &client.Client{}
+	if err := c.Connect(flags.Listen, flags.Port, flags.Timeout); err != nil {
+		return nil, fmt.Errorf("failed to connect to StarCraft II: %w", err)
+	}
+
+	return c, nil
+}
+
+// startGame creates a game on a map and joins it. StarCraft II accepts a new
+// game once the previous one is over.
+func startGame(c *client.Client, mapName string, realtime bool, bot *api.PlayerSetup, cpu *api.PlayerSetup) error {
+	mapPath := mapName + ".SC2Map"
+
+	if err := c.RequestCreateGame(mapPath, []*api.PlayerSetup{bot, cpu}, realtime); err != nil {
+		return fmt.Errorf("failed to create a game on %q: %w", mapPath, err)
+	}
+
+	if err := c.RequestJoinGame(bot, interfaceOptions, client.Ports{}); err != nil {
+		return fmt.Errorf("failed to join the game on %q: %w", mapPath, err)
+	}
+
+	return nil
+}
+
+// numberedFlags gives each game of a batch its own recording.
+func numberedFlags(flags Flags, game int) Flags {
+	flags.Record = numbered(flags.Record, game)
+	return flags
+}
+
+// numbered adds the number of a game to a path, like "game-3.rec".
+func numbered(path string, game int) string {
+	if path == "" {
+		return ""
+	}
+
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(path, ext), game, ext)
+}
+
+// printWinRates prints the win rates of a batch by difficulty and by map
+// against each race.
+func printWinRates(matches []history.Match) {
+	race := func(m history.Match) string { return m.EnemyRace }
+	difficulty := func(m history.Match) string { return m.Difficulty }
+	mapName := func(m history.Match) string { return m.Map }
+
+	byDifficulty := history.NewMatrix(matches, difficulty, race)
+	log.Info("Won %s games", byDifficulty.Total)
+
+	fmt.Println()
+	if err := byDifficulty.Write(os.Stdout, "Difficulty"); err != nil {
+		log.Warn("Failed to print the win rates by difficulty: %v", err)
+	}
+
+	fmt.Println()
+	if err := history.NewMatrix(matches, mapName, race).Write(os.Stdout, "Map"); err != nil {
+		log.Warn("Failed to print the win rates by map: %v", err)
+	}
+}
@@ -1,15 +1,57 @@
 package bot
 
 import (
+	"github.com/NatoBoram/BlackCompany/filter"
 	"github.com/aiseeq/s2l/lib/point"
 	"github.com/aiseeq/s2l/lib/scl"
 )
 
+// WaveState is what an attack wave is currently doing.
+type WaveState int
+
+const (
+	// Gathering waves wait for their units to come together.
+	Gathering WaveState = iota
+
+	// Moving waves advance towards their target.
+	Moving
+
+	// Engaging waves fight the enemies near them.
+	Engaging
+
+	// Retreating waves pull back home.
+	Retreating
+
+	// Regrouping waves wait at home for fresh units to join them.
+	Regrouping
+)
+
+func (s WaveState) String() string {
+	switch s {
+	case Gathering:
+		return "Gathering"
+	case Moving:
+		return "Moving"
+	case Engaging:
+		return "Engaging"
+	case Retreating:
+		return "Retreating"
+	case Regrouping:
+		return "Regrouping"
+	default:
+		return "Unknown"
+	}
+}
+
 // AttackWave is a single attack wave, its units and its state. It should
 // dictate the intent of its units, but the micro should be performed elsewhere.
 type AttackWave struct {
 	Tags   scl.Tags
 	Target point.Point
+	State  WaveState
+
+	// Size is how many units the wave had when it last went out.
+	Size int
 }
 
 // Units gets the units in an attack wave
@@ -20,3 +62,16 @@ func (a *AttackWave) Units(b *Bot) scl.Units {
 
 	return b.Units.MyAll.ByTags(a.Tags)
 }
+
+// RallyPoint is where the army gathers, in front of the base that's the
+// closest to the enemy.
+func (b *Bot) RallyPoint() *point.Point {
+	townHalls := b.FindTownHalls().Filter(filter.IsCcAtExpansion(b.State.CcForExp))
+	if townHalls.Empty() {
+		return nil
+	}
+
+	closest := townHalls.ClosestTo(b.Locs.EnemyStart)
+	rally := closest.Towards(b.Locs.EnemyStart, closest.SightRange())
+	return &rally
+}
@@ -1,6 +1,10 @@
 package bot
 
-import "github.com/aiseeq/s2l/lib/scl"
+import (
+	"slices"
+
+	"github.com/aiseeq/s2l/lib/scl"
+)
 
 type AttackWaves []AttackWave
 
@@ -17,3 +21,15 @@ func (a AttackWaves) Units(b *Bot) scl.Units {
 
 	return units
 }
+
+// UnitsIn gets the units of the attack waves that are in one of the states.
+func (a AttackWaves) UnitsIn(b *Bot, states ...WaveState) scl.Units {
+	var units scl.Units
+	for _, wave := range a {
+		if slices.Contains(states, wave.State) {
+			units = append(units, wave.Units(b)...)
+		}
+	}
+
+	return units
+}
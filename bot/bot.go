@@ -1,10 +1,16 @@
 package bot
 
 import (
+	"math"
+
 	"github.com/NatoBoram/BlackCompany/adapter"
+	"github.com/NatoBoram/BlackCompany/influence"
 	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/NatoBoram/BlackCompany/opponent"
+	"github.com/aiseeq/s2l/lib/point"
 	"github.com/aiseeq/s2l/lib/scl"
 	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/client"
 )
 
 // Bot holds the state of the bot.
@@ -13,9 +19,35 @@ type Bot struct {
 
 	miningInitialized bool
 
+	// opponent remembers what was scouted of the enemy.
+	opponent *opponent.Model
+
+	// Influence maps the threat of enemies. It's updated at every step.
+	Influence *influence.Map
+
 	State BotState
 }
 
+// New creates a bot that's connected to a game through the provided client.
+func New(c *client.Client) *Bot {
+	b := &Bot{
+		Bot:      scl.New(c, OnUnitCreated),
+		opponent: opponent.NewModel(),
+		State: BotState{
+			CcForExp:            make(map[api.UnitTag]point.Point),
+			CcForOrbitalCommand: 0,
+			AttackWaves:         AttackWaves{},
+			HarassSquads:        HarassSquads{},
+			EnemyMemory:         EnemyMemory{},
+		},
+	}
+
+	b.FramesPerOrder = 16
+	b.LastLoop = -math.MaxInt
+
+	return b
+}
+
 // Step is called at every step of the game. This is the main loop of the bot.
 func (b *Bot) Step() {
 	b.Cmds = &scl.CommandsStack{}
@@ -31,9 +63,16 @@ func (b *Bot) Step() {
 	b.ParseData()
 }
 
-// Observe fetches the current observation from the game.
+// Observe fetches the current observation from the game. In real-time, it
+// waits until the game reaches the next order instead of fetching the same
+// frame over and over again.
 func (b *Bot) Observe() {
-	o, err := b.Client.Observation(api.RequestObservation{})
+	request := api.RequestObservation{}
+	if b.Client.Realtime && b.Obs != nil {
+		request.GameLoop = b.Obs.GameLoop + uint32(b.FramesPerOrder)
+	}
+
+	o, err := b.Client.Observation(request)
 	if err != nil {
 		log.Info("Failed to observe: %v", err)
 		return
@@ -84,4 +123,8 @@ func (b *Bot) ParseData() {
 	b.FindClusters() // Not used yet
 
 	b.detectEnemyAirArmy()
+	b.rememberEnemies()
+	b.updateInfluence()
+	b.resolveEnemyStart()
+	b.recognizeEnemyOpening()
 }
@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"slices"
+
+	"github.com/NatoBoram/BlackCompany/combat"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+)
+
+// Simulate predicts a fight between our units and enemies. Our upgrades are
+// known, but the enemy's aren't, so they're assumed to have none.
+func (b *Bot) Simulate(allies scl.Units, enemies scl.Units) combat.Outcome {
+	ally := combat.Army{Units: allies, Upgrades: b.upgradesOf}
+	enemy := combat.Army{Units: enemies}
+	return combat.Simulate(b.U.Types, ally, enemy)
+}
+
+// FindEnemyArmyNear finds the enemies that were recently seen near a position
+// and that would fight there, including defensive structures. Workers aren't
+// counted since they rarely fight.
+func (b *Bot) FindEnemyArmyNear(pos point.Point, distance float64) scl.Units {
+	return b.State.EnemyMemory.SeenSince(b.Loop-int(threatMemory)).
+		CloserThan(distance, pos).
+		Filter(func(u *scl.Unit) bool {
+			return u.IsArmed() && !u.IsWorker()
+		})
+}
+
+// upgradesOf are our researched upgrades that apply to a unit. Only infantry
+// upgrades are researched for now.
+func (b *Bot) upgradesOf(u *scl.Unit) combat.Upgrades {
+	if u.IsStructure() || int(u.UnitType) >= len(b.U.Types) ||
+		!slices.Contains(b.U.Types[u.UnitType].Attributes, api.Attribute_Biological) {
+		return combat.Upgrades{}
+	}
+
+	return combat.Upgrades{
+		Weapons: b.levels(
+			ability.Research_TerranInfantryWeaponsLevel1,
+			ability.Research_TerranInfantryWeaponsLevel2,
+			ability.Research_TerranInfantryWeaponsLevel3,
+		),
+		Armor: b.levels(
+			ability.Research_TerranInfantryArmorLevel1,
+			ability.Research_TerranInfantryArmorLevel2,
+			ability.Research_TerranInfantryArmorLevel3,
+		),
+	}
+}
+
+// levels counts how many levels of an upgrade are researched.
+func (b *Bot) levels(researches ...api.AbilityID) int {
+	count := 0
+	for _, research := range researches {
+		if b.Upgrades[research] {
+			count++
+		}
+	}
+
+	return count
+}
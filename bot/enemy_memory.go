@@ -0,0 +1,126 @@
+package bot
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+// How long enemies are remembered after they were last seen, in game loops.
+const (
+	// unitMemory is short because units don't stay where they were seen.
+	unitMemory = 30 * scl.FPS
+
+	// structureMemory is long because structures rarely move.
+	structureMemory = 10 * 60 * scl.FPS
+
+	// threatMemory is how long enemies that were last seen near a position
+	// are still considered a threat there.
+	threatMemory = 5 * scl.FPS
+)
+
+// RememberedEnemy is an enemy unit or structure as it was when it was last
+// seen. Its position, type and health are the ones of the embedded unit.
+type RememberedEnemy struct {
+	*scl.Unit
+
+	// FirstSeen is the game loop when the enemy was first seen.
+	FirstSeen int
+
+	// LastSeen is the game loop when the enemy was last seen.
+	LastSeen int
+}
+
+// EnemyMemory remembers the enemies that were seen by their tag, even after
+// they went back in the fog of war.
+type EnemyMemory map[api.UnitTag]*RememberedEnemy
+
+// Units are every remembered enemy, sorted by tag.
+func (m EnemyMemory) Units() scl.Units {
+	units := make(scl.Units, 0, len(m))
+	for _, enemy := range m {
+		units = append(units, enemy.Unit)
+	}
+
+	slices.SortFunc(units, func(a, b *scl.Unit) int {
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+
+	return units
+}
+
+// Structures are the remembered enemy structures, sorted by tag.
+func (m EnemyMemory) Structures() scl.Units {
+	return m.Units().Filter(scl.Structure)
+}
+
+// Army finds the remembered enemy units that can fight. Workers and
+// structures aren't part of it.
+func (m EnemyMemory) Army() scl.Units {
+	return m.Units().Filter(func(u *scl.Unit) bool {
+		return u.IsArmed() && !u.IsWorker() && !u.IsStructure()
+	})
+}
+
+// Near finds the remembered enemies that are closer than a distance to a
+// position.
+func (m EnemyMemory) Near(pos point.Point, distance float64) scl.Units {
+	return m.Units().CloserThan(distance, pos)
+}
+
+// SeenSince finds the remembered enemies that were seen since a game loop.
+func (m EnemyMemory) SeenSince(loop int) scl.Units {
+	return m.Units().Filter(func(u *scl.Unit) bool {
+		return m[u.Tag].LastSeen >= loop
+	})
+}
+
+// rememberEnemies saves the enemies that are currently seen. Enemies are
+// forgotten when they die, when their position is visible but they aren't
+// there anymore, or when they weren't seen for a while.
+func (b *Bot) rememberEnemies() {
+	if b.State.EnemyMemory == nil {
+		b.State.EnemyMemory = EnemyMemory{}
+	}
+
+	seen := map[api.UnitTag]bool{}
+	for _, enemy := range b.Units.Enemy.All() {
+		// Snapshots are the game's own memory of structures
+		if enemy.DisplayType == api.DisplayType_Snapshot {
+			continue
+		}
+
+		seen[enemy.Tag] = true
+		remembered, ok := b.State.EnemyMemory[enemy.Tag]
+		if !ok {
+			remembered = &RememberedEnemy{FirstSeen: b.Loop}
+			b.State.EnemyMemory[enemy.Tag] = remembered
+		}
+
+		remembered.Unit = enemy
+		remembered.LastSeen = b.Loop
+	}
+
+	for _, tag := range b.Obs.RawData.Event.GetDeadUnits() {
+		delete(b.State.EnemyMemory, tag)
+	}
+
+	for tag, enemy := range b.State.EnemyMemory {
+		if seen[tag] {
+			continue
+		}
+
+		memory := int(unitMemory)
+		if enemy.IsStructure() {
+			memory = int(structureMemory)
+		}
+
+		gone := b.Grid != nil && b.Grid.IsVisible(enemy)
+		if gone || b.Loop-enemy.LastSeen > memory {
+			delete(b.State.EnemyMemory, tag)
+		}
+	}
+}
@@ -24,6 +24,7 @@ func (b *Bot) FindExpansionLocations() point.Points {
 	locations := make(point.Points, 0, b.Locs.MyExps.Len()+1)
 	expansions := append(b.Locs.MyExps, b.Locs.MyStart)
 	townHalls := b.FindTownHalls()
+	threats := b.State.EnemyMemory.SeenSince(b.Loop - int(threatMemory)).Filter(scl.DpsGt5)
 
 	for _, expansion := range expansions {
 		// Skip existing expansions
@@ -37,7 +38,7 @@ func (b *Bot) FindExpansionLocations() point.Points {
 		}
 
 		// Skip locations that would be unsafe
-		if b.Enemies.Visible.Filter(scl.DpsGt5).CloserThan(sight.LineOfSightScannerSweep.Float64(), expansion).Exists() {
+		if threats.CloserThan(sight.LineOfSightScannerSweep.Float64(), expansion).Exists() {
 			continue
 		}
 
@@ -72,6 +72,16 @@ func (b *Bot) FindProductionStructures() scl.Units {
 	)
 }
 
+// FindArmy finds all units that fight in attack waves. Units in harass squads
+// aren't part of it.
+func (b *Bot) FindArmy() scl.Units {
+	return b.Units.My.OfType(
+		terran.Marine, terran.Marauder,
+		terran.Hellion, terran.SiegeTank, terran.SiegeTankSieged,
+		terran.Medivac, terran.VikingFighter,
+	).Filter(filter.NotIn(b.State.HarassSquads.Units(b)))
+}
+
 // findMiners finds all units capable of mining resources.
 func (b *Bot) FindMiners() scl.Units {
 	return b.Units.My.OfType(protoss.Probe, terran.MULE, terran.SCV, zerg.Drone)
@@ -146,10 +156,11 @@ func (b *Bot) FindUnsaturatedResourcesNearTownHalls(townHalls scl.Units) scl.Uni
 func (b *Bot) FindEnemiesInBases() map[api.UnitTag]scl.Units {
 	bases := b.FindTownHalls().Filter(filter.IsCcAtExpansion(b.State.CcForExp))
 	enemiesInBases := make(map[api.UnitTag]scl.Units, len(bases))
+	recent := b.State.EnemyMemory.SeenSince(b.Loop - int(threatMemory))
 
 	for _, base := range bases {
 		buildings := scl.Units{base}
-		enemies := b.Units.Enemy.All().CloserThan(base.SightRange(), base)
+		enemies := recent.CloserThan(base.SightRange(), base)
 
 		for _, building := range buildings {
 			buildingsInSight := b.Units.MyAll.
@@ -158,7 +169,7 @@ func (b *Bot) FindEnemiesInBases() map[api.UnitTag]scl.Units {
 
 			buildings = append(buildings, buildingsInSight...)
 
-			enemiesInRange := b.Units.Enemy.All().CloserThan(sight.LineOfSightScannerSweep.Float64(), building)
+			enemiesInRange := recent.CloserThan(sight.LineOfSightScannerSweep.Float64(), building)
 			enemies = append(enemies, enemiesInRange...)
 		}
 
@@ -0,0 +1,182 @@
+package bot
+
+import (
+	"math"
+
+	"github.com/NatoBoram/BlackCompany/opponent"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+)
+
+// edgeMargin is how far from the edges of the map harass squads travel.
+const edgeMargin = 4
+
+// SquadState is what a harass squad is currently doing.
+type SquadState int
+
+const (
+	// Loading squads wait for their units to board their medivac.
+	Loading SquadState = iota
+
+	// Travelling squads follow the edges of the map to their target.
+	Travelling
+
+	// Harassing squads kill the workers of a mineral line.
+	Harassing
+
+	// Leaving squads go back home along the edges of the map.
+	Leaving
+)
+
+func (s SquadState) String() string {
+	switch s {
+	case Loading:
+		return "Loading"
+	case Travelling:
+		return "Travelling"
+	case Harassing:
+		return "Harassing"
+	case Leaving:
+		return "Leaving"
+	default:
+		return "Unknown"
+	}
+}
+
+// HarassSquad is a small wave that drops into the enemy's mineral lines to kill
+// workers instead of fighting its army.
+type HarassSquad struct {
+	Tags   scl.Tags
+	Target point.Point
+	State  SquadState
+
+	// Path are the waypoints that the squad follows to its target or back
+	// home, and Waypoint is the index of the next one.
+	Path     point.Points
+	Waypoint int
+
+	// Workers are the tags of the enemy workers that were seen near the
+	// target while the squad was harassing, to count the ones it killed.
+	Workers scl.Tags
+
+	// WorkersKilled and UnitsLost are the results of the squad.
+	WorkersKilled int
+	UnitsLost     int
+}
+
+// Units gets the units in a harass squad.
+func (s *HarassSquad) Units(b *Bot) scl.Units {
+	return b.Units.MyAll.ByTags(s.Tags)
+}
+
+type HarassSquads []HarassSquad
+
+// Units gets the units of every harass squad.
+func (h HarassSquads) Units(b *Bot) scl.Units {
+	var units scl.Units
+	for _, squad := range h {
+		units = append(units, squad.Units(b)...)
+	}
+
+	return units
+}
+
+// HarassResults are the results of every harass squad that finished.
+type HarassResults struct {
+	Squads        int
+	WorkersKilled int
+	UnitsLost     int
+}
+
+// EnemyTownHalls are the remembered enemy town halls.
+func (b *Bot) EnemyTownHalls() scl.Units {
+	return b.State.EnemyMemory.Structures().Filter(func(u *scl.Unit) bool {
+		return opponent.IsTownHall(u.UnitType)
+	})
+}
+
+// MineralLine is where the workers of a base mine, between its town hall and
+// its mineral fields.
+func (b *Bot) MineralLine(townHall point.Point) point.Point {
+	minerals := b.Units.Minerals.All().CloserThan(scl.ResourceSpreadDistance, townHall)
+	if minerals.Empty() {
+		return townHall
+	}
+
+	return minerals.Center().Towards(townHall, 2)
+}
+
+// EdgePath finds waypoints that go from a position to another along the edges
+// of the map. Of the two ways around, it picks the one that's the furthest from
+// the known enemy army, or the shortest when the army isn't known.
+func (b *Bot) EdgePath(from point.Point, to point.Point) point.Points {
+	area := b.Info.StartRaw.PlayableArea
+	edgeX := func(x float64) float64 {
+		return nearestEdge(x, float64(area.P0.X)+edgeMargin, float64(area.P1.X)-edgeMargin)
+	}
+	edgeY := func(y float64) float64 {
+		return nearestEdge(y, float64(area.P0.Y)+edgeMargin, float64(area.P1.Y)-edgeMargin)
+	}
+
+	// Along the vertical edge next to the start, then the horizontal edge
+	// next to the destination, or the other way around
+	vertical := point.Points{
+		point.Pt(edgeX(from.X()), from.Y()),
+		point.Pt(edgeX(from.X()), edgeY(to.Y())),
+		point.Pt(to.X(), edgeY(to.Y())),
+		to,
+	}
+	horizontal := point.Points{
+		point.Pt(from.X(), edgeY(from.Y())),
+		point.Pt(edgeX(to.X()), edgeY(from.Y())),
+		point.Pt(edgeX(to.X()), to.Y()),
+		to,
+	}
+
+	army := b.State.EnemyMemory.Army()
+	if army.Empty() {
+		if pathLength(from, horizontal) < pathLength(from, vertical) {
+			return horizontal
+		}
+		return vertical
+	}
+
+	if clearance(from, horizontal, army) > clearance(from, vertical, army) {
+		return horizontal
+	}
+	return vertical
+}
+
+// nearestEdge is the closest of two edges.
+func nearestEdge(v float64, low float64, high float64) float64 {
+	if v-low < high-v {
+		return low
+	}
+
+	return high
+}
+
+// pathLength is the distance travelled along waypoints.
+func pathLength(from point.Point, path point.Points) float64 {
+	length := 0.0
+	for _, p := range path {
+		length += from.Dist(p)
+		from = p
+	}
+
+	return length
+}
+
+// clearance is the shortest distance between units and the legs of a path.
+func clearance(from point.Point, path point.Points, units scl.Units) float64 {
+	closest := math.Inf(1)
+	for _, p := range path {
+		for dist := 0.0; dist < from.Dist(p); dist += edgeMargin {
+			sample := from.Towards(p, dist)
+			closest = min(closest, units.ClosestTo(sample).Dist(sample))
+		}
+		from = p
+	}
+
+	return closest
+}
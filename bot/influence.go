@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"github.com/NatoBoram/BlackCompany/influence"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+)
+
+// threatMargin is added to the range of enemy weapons so units have the time
+// to react before they get in range.
+const threatMargin = 2
+
+// updateInfluence maps the threat of the enemies that are seen, that were
+// recently seen or whose structures are remembered. Workers aren't counted
+// since they rarely fight. The map is reused from one step to the next.
+func (b *Bot) updateInfluence() {
+	if b.Influence == nil {
+		size := b.Info.StartRaw.MapSize
+		b.Influence = influence.New(int(size.X), int(size.Y))
+	} else {
+		b.Influence.Reset()
+	}
+
+	recent := b.Loop - int(threatMemory)
+	for _, enemy := range b.State.EnemyMemory.Units() {
+		if enemy.IsWorker() || !enemy.IsStructure() && b.State.EnemyMemory[enemy.Tag].LastSeen < recent {
+			continue
+		}
+
+		if dps := enemy.GroundDPS(); dps > 0 {
+			b.Influence.Add(influence.Ground, enemy.Point(), enemy.GroundRange()+float64(enemy.Radius)+threatMargin, dps)
+		}
+
+		if dps := enemy.AirDPS(); dps > 0 {
+			b.Influence.Add(influence.Air, enemy.Point(), enemy.AirRange()+float64(enemy.Radius)+threatMargin, dps)
+		}
+	}
+}
+
+// IsSafeForWorker tells if no enemy can attack a worker at a position.
+func (b *Bot) IsSafeForWorker(pos point.Point) bool {
+	return b.Influence.IsSafe(influence.Ground, pos)
+}
+
+// SafestPath finds the path that goes through the least threat for a unit to
+// reach a position. Ground units can only go through pathable cells.
+func (b *Bot) SafestPath(unit *scl.Unit, to point.Point) point.Points {
+	layer, pathable := b.layerOf(unit)
+	return b.Influence.Path(layer, unit.Point(), to, pathable)
+}
+
+// SafeRetreat finds the closest position where a unit is out of the range of
+// enemy weapons, up to a distance.
+func (b *Bot) SafeRetreat(unit *scl.Unit, distance float64) (point.Point, bool) {
+	layer, pathable := b.layerOf(unit)
+	return b.Influence.Retreat(layer, unit.Point(), distance, pathable)
+}
+
+// layerOf is the layer of the influence map that threatens a unit and the
+// cells it can cross.
+func (b *Bot) layerOf(unit *scl.Unit) (influence.Layer, func(point.Pointer) bool) {
+	if unit.IsFlying {
+		return influence.Air, nil
+	}
+
+	return influence.Ground, b.Grid.IsPathable
+}
@@ -0,0 +1,38 @@
+package bot
+
+import "github.com/aiseeq/s2l/lib/scl"
+
+// Ledger reserves resources for build steps that are waiting for them. Reserved
+// resources are deducted from the bot, so `CanBuy` refuses anything that would
+// spend them.
+type Ledger struct {
+	reserved scl.Cost
+}
+
+// Reserve deducts a cost from the bot's resources like `DeductResources`.
+func (l *Ledger) Reserve(b *Bot, cost scl.Cost) {
+	b.Minerals -= cost.Minerals
+	b.Vespene -= cost.Vespene
+	l.reserved.Minerals += cost.Minerals
+	l.reserved.Vespene += cost.Vespene
+
+	if cost.Food > 0 {
+		b.FoodUsed += cost.Food
+		b.FoodLeft -= cost.Food
+		l.reserved.Food += cost.Food
+	}
+}
+
+// Release gives back every reserved resource to the bot.
+func (l *Ledger) Release(b *Bot) {
+	b.Minerals += l.reserved.Minerals
+	b.Vespene += l.reserved.Vespene
+	b.FoodUsed -= l.reserved.Food
+	b.FoodLeft += l.reserved.Food
+	l.reserved = scl.Cost{}
+}
+
+// Reserved is the total of the resources currently reserved.
+func (l *Ledger) Reserved() scl.Cost {
+	return l.reserved
+}
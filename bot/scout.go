@@ -0,0 +1,99 @@
+package bot
+
+import (
+	"slices"
+
+	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/NatoBoram/BlackCompany/opponent"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+// enemyMainRadius is the distance from a start location where enemy
+// structures are considered to be in that main base.
+const enemyMainRadius = 20
+
+// Scout is the unit that's scouting the enemy and where it's going.
+type Scout struct {
+	Tag api.UnitTag
+
+	// Route are the locations the scout still has to visit.
+	Route point.Points
+
+	// Trips counts the scouting trips that were started.
+	Trips int
+
+	// Next is the game loop at which the next scouting trip can start.
+	Next int
+}
+
+// initEnemyStarts lists the start locations where the enemy might be.
+func (b *Bot) initEnemyStarts() {
+	b.State.EnemyStarts = slices.Clone(b.Locs.EnemyStarts)
+	if len(b.State.EnemyStarts) == 0 {
+		b.State.EnemyStarts = point.Points{b.Locs.EnemyStart}
+	}
+}
+
+// resolveEnemyStart narrows down the start locations where the enemy might be.
+// A start location is the enemy's when one of its town halls was seen there,
+// and it's not when it's visible without any enemy structure.
+func (b *Bot) resolveEnemyStart() {
+	if len(b.State.EnemyStarts) <= 1 {
+		return
+	}
+
+	for _, start := range b.State.EnemyStarts {
+		for _, structure := range b.State.EnemyMemory.Near(start, enemyMainRadius).Filter(scl.Structure) {
+			if opponent.IsTownHall(structure.UnitType) {
+				b.setEnemyStart(start)
+				return
+			}
+		}
+	}
+
+	if b.Grid == nil {
+		return
+	}
+
+	for _, start := range slices.Clone(b.State.EnemyStarts) {
+		if len(b.State.EnemyStarts) <= 1 {
+			break
+		}
+
+		if b.Grid.IsVisible(start) && b.State.EnemyMemory.Near(start, enemyMainRadius).Filter(scl.Structure).Empty() {
+			log.Info("The enemy didn't start at %v", start)
+			b.State.EnemyStarts.Remove(start)
+		}
+	}
+
+	if len(b.State.EnemyStarts) == 1 {
+		b.setEnemyStart(b.State.EnemyStarts[0])
+	}
+}
+
+// setEnemyStart moves the enemy's start location and its expansions to the
+// one that was found by scouting.
+func (b *Bot) setEnemyStart(start point.Point) {
+	b.State.EnemyStarts = point.Points{start}
+	if b.Locs.EnemyStart == start {
+		return
+	}
+
+	log.Info("Found the enemy's start location at %v", start)
+	b.Locs.EnemyStart = start
+	b.Locs.EnemyMainCenter = b.FindBaseCenter(start)
+	b.FindExpansions()
+}
+
+// EnemyNatural is the enemy's closest expansion that isn't its main base.
+func (b *Bot) EnemyNatural() (point.Point, bool) {
+	for _, expansion := range b.Locs.EnemyExps {
+		if expansion.IsFurtherThan(scl.ResourceSpreadDistance, b.Locs.EnemyStart) {
+			return expansion, true
+		}
+	}
+
+	return 0, false
+}
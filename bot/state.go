@@ -2,6 +2,7 @@ package bot
 
 import (
 	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/NatoBoram/BlackCompany/opponent"
 	"github.com/NatoBoram/BlackCompany/quote"
 	"github.com/NatoBoram/BlackCompany/wheel"
 	"github.com/aiseeq/s2l/lib/point"
@@ -24,15 +25,47 @@ type BotState struct {
 	// tech lab.
 	BuildingForAddOn api.UnitTag
 
+	// VersionAnnounced tells if the version of the bot was announced in the
+	// chat.
+	VersionAnnounced bool
+
+	// FirstWaves counts the first attack waves that were launched.
+	FirstWaves int
+
+	// ScanReserved tells if orbital commands are keeping energy for a scanner
+	// sweep.
+	ScanReserved bool
+
 	// AttackWaves holds the groups of units that are used for attacking.
 	AttackWaves AttackWaves
 
+	// HarassSquads holds the small groups of units that harass the enemy's
+	// mineral lines.
+	HarassSquads HarassSquads
+
+	// Harassment sums up the results of the harass squads that finished.
+	Harassment HarassResults
+
 	// DetectedEnemyAirArmy saves whether the bot has seen any air units.
 	DetectedEnemyAirArmy bool
+
+	// EnemyMemory remembers the enemies that were seen.
+	EnemyMemory EnemyMemory
+
+	// EnemyStarts are the start locations where the enemy might be. Scouting
+	// narrows them down to a single one.
+	EnemyStarts point.Points
+
+	// Scout is the unit that's scouting the enemy.
+	Scout Scout
+
+	// EnemyOpening is the enemy's opening, as recognized from what was scouted.
+	EnemyOpening opponent.Classification
 }
 
 func (b *Bot) InitState() {
 	b.initCcForExp()
+	b.initEnemyStarts()
 }
 
 func (b *Bot) initCcForExp() {
@@ -69,3 +102,15 @@ func (b *Bot) detectEnemyAirArmy() {
 		b.Actions.ChatSend(message, api.ActionChat_Broadcast)
 	}
 }
+
+// recognizeEnemyOpening updates the enemy's opening with what's currently
+// visible.
+func (b *Bot) recognizeEnemyOpening() {
+	b.opponent.Observe(b.Bot)
+
+	previous := b.State.EnemyOpening.Opening
+	b.State.EnemyOpening = b.opponent.Classify()
+	if b.State.EnemyOpening.Opening != previous {
+		log.Info("Recognized the enemy's opening as %s.", b.State.EnemyOpening)
+	}
+}
@@ -1,9 +1,30 @@
 package bot
 
+import (
+	"slices"
+
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
 // Strategy is a build order to be executed.
 type Strategy struct {
 	Name  string
 	Steps BuildOrder
+
+	// Parallel lets the steps after a blocked step run as long as they don't
+	// need the resources the blocked step is saving for.
+	Parallel bool
+
+	// EnemyRaces are the enemy races this strategy is meant to play against.
+	// When it's empty, the strategy can be played against any race.
+	EnemyRaces []api.Race
+}
+
+// IsMeantFor tells if a strategy is meant to be played against the provided
+// race.
+func (s *Strategy) IsMeantFor(race api.Race) bool {
+	return len(s.EnemyRaces) == 0 || slices.Contains(s.EnemyRaces, race)
 }
 
 // BuildOrder is a list of build steps in a strategy.
@@ -22,4 +43,15 @@ type BuildStep struct {
 
 	// Next determines if we're ready to advance to the next step.
 	Next func(*Bot) bool
+
+	// Cost is the minerals, vespene and supply this step is saving for. When
+	// it's nil, the step doesn't let the following steps run in parallel.
+	Cost func(*Bot) scl.Cost
+}
+
+// AbilityCost declares that a step costs as much as an ability.
+func AbilityCost(abilityId api.AbilityID) func(*Bot) scl.Cost {
+	return func(b *Bot) scl.Cost {
+		return b.U.AbilityCost[abilityId]
+	}
 }
@@ -0,0 +1,251 @@
+package combat
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+const (
+	// step is the duration of a round of the simulation, in game seconds.
+	step = 0.25
+
+	// maxDuration stops fights that nobody can win, like units that can't
+	// attack each other, in game seconds.
+	maxDuration = 60
+
+	// minDamage is the damage dealt by an attack when armor is higher than
+	// its damage.
+	minDamage = 0.5
+)
+
+// Side is one of the two armies of a fight.
+type Side int
+
+const (
+	Draw Side = iota
+	Ally
+	Enemy
+)
+
+func (s Side) String() string {
+	switch s {
+	case Ally:
+		return "Ally"
+	case Enemy:
+		return "Enemy"
+	default:
+		return "Draw"
+	}
+}
+
+// Upgrades are the weapons and armor levels of a unit. Each level adds one
+// damage to each attack and one armor.
+type Upgrades struct {
+	Weapons int
+	Armor   int
+}
+
+// Army is a group of units that fight together.
+type Army struct {
+	Units scl.Units
+
+	// Upgrades gives the upgrades that apply to a unit. When it's nil, units
+	// don't have any upgrade.
+	Upgrades func(*scl.Unit) Upgrades
+}
+
+// Outcome is the prediction of a fight.
+type Outcome struct {
+	// Winner is the side that still has units at the end of the fight.
+	Winner Side
+
+	// Duration is how long the fight lasts, in game seconds.
+	Duration float64
+
+	// AllyLosses and EnemyLosses are the units that die during the fight.
+	AllyLosses  scl.Units
+	EnemyLosses scl.Units
+
+	// AllyLostValue and EnemyLostValue are the minerals and vespene spent on
+	// the units that die during the fight.
+	AllyLostValue  float64
+	EnemyLostValue float64
+}
+
+// Simulate predicts a fight between two armies using the game's unit data.
+// Every unit is assumed to reach its targets and each one focuses the enemy
+// with the lowest health that it can attack, without wasting damage on units
+// that are already dying. Movement, healing, spells and splash damage are
+// ignored.
+func Simulate(types []*api.UnitTypeData, ally Army, enemy Army) Outcome {
+	sides := [2][]*fighter{newFighters(types, ally), newFighters(types, enemy)}
+
+	elapsed := 0.0
+	for elapsed < maxDuration && alive(sides[0]) && alive(sides[1]) {
+		pending := map[*fighter]float64{}
+
+		for side, fighters := range sides {
+			for _, f := range fighters {
+				if f.hits <= 0 {
+					continue
+				}
+
+				target, dps := f.aim(sides[1-side], pending)
+				if target != nil {
+					pending[target] += dps * step
+				}
+			}
+		}
+
+		for target, damage := range pending {
+			target.hits -= damage
+		}
+
+		elapsed += step
+	}
+
+	outcome := Outcome{Duration: elapsed}
+	switch {
+	case alive(sides[0]) && !alive(sides[1]):
+		outcome.Winner = Ally
+	case alive(sides[1]) && !alive(sides[0]):
+		outcome.Winner = Enemy
+	}
+
+	outcome.AllyLosses, outcome.AllyLostValue = losses(sides[0])
+	outcome.EnemyLosses, outcome.EnemyLostValue = losses(sides[1])
+	return outcome
+}
+
+// fighter is the state of a unit during a simulation.
+type fighter struct {
+	unit     *scl.Unit
+	data     *api.UnitTypeData
+	hits     float64
+	upgrades Upgrades
+}
+
+// newFighters prepares the units of an army for a simulation, sorted by tag so
+// the simulation is deterministic.
+func newFighters(types []*api.UnitTypeData, army Army) []*fighter {
+	units := slices.Clone(army.Units)
+	slices.SortFunc(units, func(a, b *scl.Unit) int {
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+
+	fighters := make([]*fighter, 0, len(units))
+	for _, u := range units {
+		data := &api.UnitTypeData{UnitId: u.UnitType}
+		if int(u.UnitType) < len(types) && types[u.UnitType] != nil {
+			data = types[u.UnitType]
+		}
+
+		f := &fighter{unit: u, data: data, hits: u.Hits}
+		if army.Upgrades != nil {
+			f.upgrades = army.Upgrades(u)
+		}
+
+		fighters = append(fighters, f)
+	}
+
+	return fighters
+}
+
+// aim finds the enemy that a fighter attacks and the damage per second it
+// deals to it. Enemies that will already die from the pending damage are only
+// attacked when there's nothing else to attack.
+func (f *fighter) aim(enemies []*fighter, pending map[*fighter]float64) (*fighter, float64) {
+	var best, overkill *fighter
+	var bestDPS, overkillDPS float64
+
+	for _, enemy := range enemies {
+		if enemy.hits <= 0 {
+			continue
+		}
+
+		dps := f.dps(enemy)
+		if dps <= 0 {
+			continue
+		}
+
+		remaining := enemy.hits - pending[enemy]
+		if remaining <= 0 {
+			if overkill == nil || enemy.hits < overkill.hits {
+				overkill, overkillDPS = enemy, dps
+			}
+			continue
+		}
+
+		if best == nil || remaining < best.hits-pending[best] {
+			best, bestDPS = enemy, dps
+		}
+	}
+
+	if best == nil {
+		return overkill, overkillDPS
+	}
+
+	return best, bestDPS
+}
+
+// dps is the damage per second that a fighter deals to a target with its best
+// weapon against it.
+func (f *fighter) dps(target *fighter) float64 {
+	best := 0.0
+	for _, weapon := range f.data.Weapons {
+		if !canHit(weapon, target.unit) || weapon.Speed <= 0 {
+			continue
+		}
+
+		damage := float64(weapon.Damage) + float64(f.upgrades.Weapons)
+		for _, bonus := range weapon.DamageBonus {
+			if slices.Contains(target.data.Attributes, bonus.Attribute) {
+				damage += float64(bonus.Bonus)
+			}
+		}
+
+		damage = max(minDamage, damage-float64(target.data.Armor)-float64(target.upgrades.Armor))
+		best = max(best, damage*float64(weapon.Attacks)/float64(weapon.Speed))
+	}
+
+	return best
+}
+
+// canHit tells if a weapon can attack a unit.
+func canHit(weapon *api.Weapon, unit *scl.Unit) bool {
+	switch weapon.Type {
+	case api.Weapon_Ground:
+		return !unit.IsFlying
+	case api.Weapon_Air:
+		return unit.IsFlying
+	default:
+		return true
+	}
+}
+
+// alive tells if at least one fighter is still alive.
+func alive(fighters []*fighter) bool {
+	return slices.ContainsFunc(fighters, func(f *fighter) bool {
+		return f.hits > 0
+	})
+}
+
+// losses lists the fighters that died and the resources spent on them.
+func losses(fighters []*fighter) (scl.Units, float64) {
+	var units scl.Units
+	value := 0.0
+
+	for _, f := range fighters {
+		if f.hits > 0 {
+			continue
+		}
+
+		units = append(units, f.unit)
+		value += float64(f.data.MineralCost + f.data.VespeneCost)
+	}
+
+	return units, value
+}
@@ -0,0 +1,104 @@
+package combat_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/combat"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+// units creates a group of units at full health.
+func units(tag api.UnitTag, unitType api.UnitTypeID, count int, hits float64) scl.Units {
+	group := make(scl.Units, 0, count)
+	for i := range count {
+		group = append(group, &scl.Unit{
+			Unit: api.Unit{Tag: tag + api.UnitTag(i), UnitType: unitType},
+			Hits: hits,
+		})
+	}
+
+	return group
+}
+
+func TestSimulate_Winner(t *testing.T) {
+	types := sim.Data().Units
+
+	tests := []struct {
+		name    string
+		ally    scl.Units
+		enemy   scl.Units
+		winner  combat.Side
+		minTime float64
+	}{
+		{"more marines win", units(1, terran.Marine, 10, 45), units(100, terran.Marine, 5, 45), combat.Ally, 1},
+		{"roaches beat marines", units(1, terran.Marine, 6, 45), units(100, zerg.Roach, 10, 145), combat.Enemy, 1},
+		{"unarmed units can't win", units(1, terran.Medivac, 2, 150), units(100, zerg.Overlord, 2, 200), combat.Draw, 60},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outcome := combat.Simulate(types, combat.Army{Units: test.ally}, combat.Army{Units: test.enemy})
+
+			if outcome.Winner != test.winner {
+				t.Errorf("Winner = %v, expected %v", outcome.Winner, test.winner)
+			}
+
+			if outcome.Duration < test.minTime {
+				t.Errorf("Duration = %v, expected at least %v", outcome.Duration, test.minTime)
+			}
+		})
+	}
+}
+
+func TestSimulate_Losses(t *testing.T) {
+	types := sim.Data().Units
+	ally := units(1, terran.Marine, 10, 45)
+	enemy := units(100, terran.Marine, 5, 45)
+
+	outcome := combat.Simulate(types, combat.Army{Units: ally}, combat.Army{Units: enemy})
+
+	if got := outcome.EnemyLosses.Len(); got != 5 {
+		t.Errorf("EnemyLosses.Len() = %d, expected %d", got, 5)
+	}
+
+	if got := outcome.EnemyLostValue; got != 250 {
+		t.Errorf("EnemyLostValue = %v, expected %v", got, 250)
+	}
+
+	if got := outcome.AllyLosses.Len(); got == 0 || got >= 5 {
+		t.Errorf("AllyLosses.Len() = %d, expected between %d and %d", got, 1, 4)
+	}
+}
+
+func TestSimulate_Upgrades(t *testing.T) {
+	types := sim.Data().Units
+	ally := units(1, terran.Marine, 8, 45)
+	enemy := units(100, terran.Marine, 8, 45)
+
+	upgraded := func(*scl.Unit) combat.Upgrades {
+		return combat.Upgrades{Weapons: 2, Armor: 2}
+	}
+
+	outcome := combat.Simulate(types, combat.Army{Units: ally, Upgrades: upgraded}, combat.Army{Units: enemy})
+	if outcome.Winner != combat.Ally {
+		t.Errorf("Winner = %v, expected %v", outcome.Winner, combat.Ally)
+	}
+}
+
+func TestSimulate_Deterministic(t *testing.T) {
+	types := sim.Data().Units
+	ally := units(1, terran.Marine, 7, 45)
+	enemy := units(100, zerg.Zergling, 14, 35)
+
+	first := combat.Simulate(types, combat.Army{Units: ally}, combat.Army{Units: enemy})
+	for range 10 {
+		outcome := combat.Simulate(types, combat.Army{Units: ally}, combat.Army{Units: enemy})
+		if outcome.Winner != first.Winner || outcome.Duration != first.Duration || outcome.AllyLostValue != first.AllyLostValue {
+			t.Fatalf("Simulate() = %+v, expected %+v", outcome, first)
+		}
+	}
+}
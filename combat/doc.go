@@ -0,0 +1,4 @@
+// combat predicts the outcome of fights between two groups of units. The
+// simulation is fast and deterministic so it can be run every frame to decide
+// whether an army should engage, hold its position or retreat.
+package combat
@@ -45,18 +45,125 @@ type Flags struct {
 	// Timeout for how long the library will block for a response.
 	Timeout time.Duration
 
-	// Replay is the path to a replay file.
+	// Replay is the path to a replay file. Instead of playing, the bot steps
+	// through the replay and exports what each player had over time.
 	Replay string
 
-	// Map is the name of a map to load.
+	// ReplayPlayer is the player whose point of view is used to analyze the
+	// replay.
+	//
+	// Default: 1
+	ReplayPlayer int
+
+	// Timeline is the path of a file where the timeline of an analyzed replay
+	// is saved, as CSV if it ends with ".csv" or as JSON otherwise.
+	Timeline string
+
+	// Replays is a directory of replays. Instead of playing, the bot observes
+	// each replay once for each player and saves their build orders.
+	Replays string
+
+	// BuildOrders is the directory where the build orders extracted from
+	// Replays are saved.
+	//
+	// Default: build-orders
+	BuildOrders string
+
+	// Map is the name of a map to load, like "SiteDelta513AIE". When it's
+	// empty, a random ladder map is played.
 	Map string
+
+	// Record is the path of a file where the game will be recorded.
+	Record string
+
+	// Playback is the path of a recorded game to feed to the bot instead of
+	// launching StarCraft II.
+	Playback string
+
+	// Strategy is the name of the strategy to play, or "random" to pick one
+	// that's meant for the enemy's race. The strategy is picked when the game
+	// starts, so against a Random opponent only the strategies meant for any
+	// race can be picked.
+	//
+	// Default: Standard, or the strategy loaded with StrategyFile
+	Strategy string
+
+	// StrategyFile is the path of a YAML or JSON file describing a strategy.
+	// The strategy can then be selected by name.
+	StrategyFile string
+
+	// Trace is the path of a file where the timings of the build order are
+	// saved at the end of the game, as CSV if it ends with ".csv" or as JSON
+	// otherwise.
+	Trace string
+
+	// History is the path of the JSON-lines file where the result of each game
+	// is added. An empty path disables the match history.
+	//
+	// Default: history.jsonl
+	History string
+
+	// Games is how many games to play in a row against the built-in AI while
+	// cycling through its races and difficulties and the ladder maps. 0 plays a
+	// single game.
+	Games int
+
+	// Opponent is who the bot plays against, either "computer" for the built-in
+	// AI or "bot" for another instance of this bot.
+	//
+	// Default: computer
+	Opponent string
+
+	// Race is the opponent's race.
+	//
+	// Default: random
+	Race string
+
+	// Difficulty is the difficulty of the built-in AI, like "hard" or
+	// "cheatinsane".
+	//
+	// Default: hard
+	Difficulty string
+
+	// Build is the build of the built-in AI, either "random", "rush", "timing",
+	// "power", "macro" or "air".
+	//
+	// Default: random
+	Build string
+
+	// OpponentStrategy is the strategy played by the opponent when it's another
+	// instance of this bot.
+	//
+	// Default: the same as Strategy
+	OpponentStrategy string
+
+	// Ladder flags
+
+	// GamePort is the port of a StarCraft II instance where the bot joins a
+	// game hosted by another process instead of creating its own.
+	GamePort int
+
+	// StartPort is the first of the ports used by the players of a hosted game
+	// to talk to each other.
+	StartPort int
+
+	// LadderServer is the address of the StarCraft II instance launched by a
+	// ladder manager. When it's set, the bot joins the game at GamePort
+	// without launching StarCraft II.
+	LadderServer string
+
+	// OpponentId is the ladder's identifier of the opponent.
+	OpponentId string
+
+	// ListStrategies prints the available strategies instead of playing.
+	ListStrategies bool
 }
 
 func loadFlags() Flags {
 	parsed := ParseFlags()
 
 	flags := Flags{}
-	flags.Realtime = flagBool(parsed, "realtime", false)
+	flags.Realtime = flagBool(parsed, "realtime", flagBool(parsed, "RealTime", false))
 	flags.DisplayMode = flagInt(parsed, "displaymode", 0)
 	flags.Port = flagInt(parsed, "port", 8168)
 	flags.WindowHeight = flagInt(parsed, "windowheight", 0)
@@ -65,7 +172,28 @@ func loadFlags() Flags {
 	flags.WindowY = flagInt(parsed, "windowy", 0)
 	flags.Listen = flagString(parsed, "listen", "127.0.0.1")
 	flags.Replay = flagString(parsed, "replay", "")
+	flags.ReplayPlayer = flagInt(parsed, "replay-player", 1)
+	flags.Timeline = flagString(parsed, "timeline", "")
+	flags.Replays = flagString(parsed, "replays", "")
+	flags.BuildOrders = flagString(parsed, "build-orders", "build-orders")
 	flags.Map = flagString(parsed, "map", "")
+	flags.Record = flagString(parsed, "record", "")
+	flags.Playback = flagString(parsed, "playback", "")
+	flags.Strategy = flagString(parsed, "strategy", "")
+	flags.StrategyFile = flagString(parsed, "strategy-file", "")
+	flags.Trace = flagString(parsed, "trace", "")
+	flags.History = flagString(parsed, "history", "history.jsonl")
+	flags.Games = flagInt(parsed, "games", 0)
+	flags.Opponent = flagString(parsed, "opponent", OpponentComputer)
+	flags.Race = flagString(parsed, "race", "random")
+	flags.Difficulty = flagString(parsed, "difficulty", "hard")
+	flags.Build = flagString(parsed, "build", "random")
+	flags.OpponentStrategy = flagString(parsed, "opponent-strategy", "")
+	flags.GamePort = flagInt(parsed, "GamePort", 0)
+	flags.StartPort = flagInt(parsed, "StartPort", 0)
+	flags.LadderServer = flagString(parsed, "LadderServer", "")
+	flags.OpponentId = flagString(parsed, "OpponentId", "")
+	flags.ListStrategies = flagBool(parsed, "list-strategies", false)
 
 	twoMinutes, _ := time.ParseDuration("2m")
 	flags.Timeout = flagDuration(parsed, "timeout", twoMinutes)
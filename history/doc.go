@@ -0,0 +1,3 @@
+// history keeps the results of the games played by the bot in a JSON-lines
+// file so win rates can be tracked per map, race and strategy.
+package history
@@ -0,0 +1,67 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Append adds a match at the end of a history file, creating it if needed.
+func Append(path string, match Match) error {
+	line, err := json.Marshal(match)
+	if err != nil {
+		return fmt.Errorf("failed to encode match: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file %q: %w", path, err)
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write to history file %q: %w", path, err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close history file %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// Load reads every match in a history file. A missing file is an empty
+// history.
+func Load(path string) ([]Match, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file %q: %w", path, err)
+	}
+	defer file.Close()
+
+	var matches []Match
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var match Match
+		if err := json.Unmarshal(scanner.Bytes(), &match); err != nil {
+			return nil, fmt.Errorf("failed to decode line %d of history file %q: %w", line, path, err)
+		}
+
+		matches = append(matches, match)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file %q: %w", path, err)
+	}
+
+	return matches, nil
+}
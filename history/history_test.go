@@ -0,0 +1,132 @@
+package history_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/agent"
+	"github.com/NatoBoram/BlackCompany/history"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+func TestNewMatch(t *testing.T) {
+	game := &agent.Game{
+		Map:        "Site Delta LE",
+		Strategy:   "Standard",
+		EnemyRace:  api.Race_Zerg,
+		Result:     api.Result_Victory,
+		Loops:      13440,
+		PeakSupply: 143,
+		Score: &api.Score{ScoreDetails: &api.ScoreDetails{
+			KilledMinerals:        &api.CategoryScoreDetails{Army: 2800, Economy: 600},
+			KilledVespene:         &api.CategoryScoreDetails{Army: 800},
+			KilledValueStructures: 1500,
+			LostMinerals:          &api.CategoryScoreDetails{Army: 900, Economy: 300},
+			LostVespene:           &api.CategoryScoreDetails{Army: 250},
+		}},
+	}
+
+	match := history.NewMatch(game, api.Difficulty_Hard)
+
+	if match.EnemyRace != "Zerg" {
+		t.Errorf("EnemyRace = %q, expected %q", match.EnemyRace, "Zerg")
+	}
+
+	if match.Difficulty != "Hard" {
+		t.Errorf("Difficulty = %q, expected %q", match.Difficulty, "Hard")
+	}
+
+	if !match.Won() {
+		t.Errorf("Won() = %v, expected %v", match.Won(), true)
+	}
+
+	if match.Seconds != 600 {
+		t.Errorf("Seconds = %d, expected %d", match.Seconds, 600)
+	}
+
+	if match.UnitsKilled != 4200 {
+		t.Errorf("UnitsKilled = %d, expected %d", match.UnitsKilled, 4200)
+	}
+
+	if match.UnitsLost != 1450 {
+		t.Errorf("UnitsLost = %d, expected %d", match.UnitsLost, 1450)
+	}
+}
+
+func TestAppend_Load(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	first := history.Match{Map: "Site Delta LE", Result: "Victory"}
+	second := history.Match{Map: "Pylon LE", Result: "Defeat"}
+	for _, match := range []history.Match{first, second} {
+		if err := history.Append(path, match); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	matches, err := history.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(matches) != 2 {
+		t.Fatalf("len(matches) = %d, expected %d", len(matches), 2)
+	}
+
+	if matches[0].Map != first.Map || matches[1].Map != second.Map {
+		t.Errorf("matches = %v, expected %v and %v", matches, first, second)
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	matches, err := history.Load(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(matches) != 0 {
+		t.Errorf("len(matches) = %d, expected %d", len(matches), 0)
+	}
+}
+
+func TestNewMatrix(t *testing.T) {
+	matches := []history.Match{
+		{Map: "Pylon LE", EnemyRace: "Zerg", Result: "Victory"},
+		{Map: "Pylon LE", EnemyRace: "Zerg", Result: "Defeat"},
+		{Map: "Pylon LE", EnemyRace: "Terran", Result: "Victory"},
+		{Map: "Site Delta LE", EnemyRace: "Zerg", Result: "Victory"},
+	}
+
+	matrix := history.NewMatrix(matches,
+		func(m history.Match) string { return m.Map },
+		func(m history.Match) string { return m.EnemyRace },
+	)
+
+	if cell := matrix.Cell("Pylon LE", "Zerg"); cell != (history.WinRate{Wins: 1, Games: 2}) {
+		t.Errorf("Cell(Pylon LE, Zerg) = %v, expected %v", cell, "1/2 (50%)")
+	}
+
+	if cell := matrix.Cell("Site Delta LE", "Terran"); cell.String() != "-" {
+		t.Errorf("Cell(Site Delta LE, Terran) = %v, expected %v", cell, "-")
+	}
+
+	if column := matrix.Column("Zerg"); column != (history.WinRate{Wins: 2, Games: 3}) {
+		t.Errorf("Column(Zerg) = %v, expected %v", column, "2/3 (67%)")
+	}
+
+	if matrix.Total.String() != "3/4 (75%)" {
+		t.Errorf("Total = %v, expected %v", matrix.Total, "3/4 (75%)")
+	}
+
+	var buffer bytes.Buffer
+	if err := matrix.Write(&buffer, "Map"); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 4 {
+		t.Errorf("len(lines) = %d, expected %d", len(lines), 4)
+	}
+}
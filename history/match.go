@@ -0,0 +1,120 @@
+package history
+
+import (
+	"runtime/debug"
+	"time"
+
+	"github.com/NatoBoram/BlackCompany/agent"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+// Match is the record of a game played by the bot.
+type Match struct {
+	// Time is when the game ended.
+	Time time.Time `json:"time"`
+
+	Map        string `json:"map"`
+	EnemyRace  string `json:"enemyRace"`
+	Difficulty string `json:"difficulty,omitempty"`
+	Strategy   string `json:"strategy"`
+
+	// OpponentId identifies the opponent on a ladder.
+	OpponentId string `json:"opponentId,omitempty"`
+
+	// Result is "Victory", "Defeat", "Tie" or "Undecided".
+	Result string `json:"result"`
+
+	// Loops is the length of the game in game loops.
+	Loops int `json:"loops"`
+
+	// Seconds is the length of the game in game seconds.
+	Seconds int `json:"seconds"`
+
+	PeakSupply int `json:"peakSupply"`
+
+	// UnitsKilled and StructuresKilled are the value of the enemies killed, in
+	// minerals and vespene.
+	UnitsKilled      int `json:"unitsKilled"`
+	StructuresKilled int `json:"structuresKilled"`
+
+	// UnitsLost is the value of the army and workers lost, in minerals and
+	// vespene, counted the same way as UnitsKilled.
+	UnitsLost int `json:"unitsLost"`
+
+	// Version is the version of the bot that played the game.
+	Version string `json:"version"`
+}
+
+// NewMatch creates the record of a game.
+func NewMatch(game *agent.Game, difficulty api.Difficulty) Match {
+	match := Match{
+		Time:       time.Now(),
+		Map:        game.Map,
+		EnemyRace:  game.EnemyRace.String(),
+		Strategy:   game.Strategy,
+		Result:     game.Result.String(),
+		Loops:      game.Loops,
+		Seconds:    int(float64(game.Loops) / scl.FPS),
+		PeakSupply: game.PeakSupply,
+		Version:    Version(),
+	}
+
+	// Other bots don't have a difficulty
+	if difficulty != api.Difficulty_nil {
+		match.Difficulty = difficulty.String()
+	}
+
+	if details := game.Score.GetScoreDetails(); details != nil {
+		match.UnitsKilled = unitsValue(details.KilledMinerals, details.KilledVespene)
+		match.StructuresKilled = int(details.KilledValueStructures)
+		match.UnitsLost = unitsValue(details.LostMinerals, details.LostVespene)
+	}
+
+	return match
+}
+
+// Won tells if the bot won the match.
+func (m Match) Won() bool {
+	return m.Result == api.Result_Victory.String()
+}
+
+// Version is the version of the bot, including the commit it was built from
+// when it's known.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	version := info.Main.Version
+	var revision, modified string
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value
+		}
+	}
+
+	if revision == "" {
+		return version
+	}
+
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+
+	if modified == "true" {
+		revision += "-dirty"
+	}
+
+	return version + "+" + revision
+}
+
+// unitsValue is the value of the army and the economy of a score category, in
+// minerals and vespene.
+func unitsValue(minerals *api.CategoryScoreDetails, vespene *api.CategoryScoreDetails) int {
+	return int(minerals.GetArmy() + minerals.GetEconomy() + vespene.GetArmy() + vespene.GetEconomy())
+}
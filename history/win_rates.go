@@ -0,0 +1,140 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+)
+
+// WinRate counts the games won out of the games played.
+type WinRate struct {
+	Wins  int
+	Games int
+}
+
+// add counts a match.
+func (w *WinRate) add(match Match) {
+	w.Games++
+	if match.Won() {
+		w.Wins++
+	}
+}
+
+// String formats the win rate like "3/4 (75%)", or "-" when no games were
+// played.
+func (w WinRate) String() string {
+	if w.Games == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d/%d (%.0f%%)", w.Wins, w.Games, float64(w.Wins)/float64(w.Games)*100)
+}
+
+// Matrix is a table of win rates grouped by two properties of the matches.
+type Matrix struct {
+	Rows    []string
+	Columns []string
+
+	// Cells are the win rates indexed by row then by column.
+	Cells map[string]map[string]*WinRate
+
+	// Total is the win rate of every match.
+	Total WinRate
+}
+
+// NewMatrix groups matches by the provided rows and columns, like the map and
+// the enemy's race. Rows and columns are sorted alphabetically.
+func NewMatrix(matches []Match, row func(Match) string, column func(Match) string) *Matrix {
+	matrix := &Matrix{Cells: make(map[string]map[string]*WinRate)}
+
+	for _, match := range matches {
+		r, c := row(match), column(match)
+
+		if _, ok := matrix.Cells[r]; !ok {
+			matrix.Cells[r] = make(map[string]*WinRate)
+			matrix.Rows = append(matrix.Rows, r)
+		}
+
+		if !slices.Contains(matrix.Columns, c) {
+			matrix.Columns = append(matrix.Columns, c)
+		}
+
+		cell, ok := matrix.Cells[r][c]
+		if !ok {
+			cell = &WinRate{}
+			matrix.Cells[r][c] = cell
+		}
+
+		cell.add(match)
+		matrix.Total.add(match)
+	}
+
+	slices.Sort(matrix.Rows)
+	slices.Sort(matrix.Columns)
+	return matrix
+}
+
+// Cell is the win rate of a row and a column.
+func (m *Matrix) Cell(row string, column string) WinRate {
+	if cell, ok := m.Cells[row][column]; ok {
+		return *cell
+	}
+
+	return WinRate{}
+}
+
+// Row is the win rate of every match in a row.
+func (m *Matrix) Row(row string) WinRate {
+	total := WinRate{}
+	for _, cell := range m.Cells[row] {
+		total.Wins += cell.Wins
+		total.Games += cell.Games
+	}
+
+	return total
+}
+
+// Column is the win rate of every match in a column.
+func (m *Matrix) Column(column string) WinRate {
+	total := WinRate{}
+	for _, cells := range m.Cells {
+		if cell, ok := cells[column]; ok {
+			total.Wins += cell.Wins
+			total.Games += cell.Games
+		}
+	}
+
+	return total
+}
+
+// Write prints the matrix as a table with the totals of each row and column.
+func (m *Matrix) Write(w io.Writer, title string) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprint(table, title)
+	for _, column := range m.Columns {
+		fmt.Fprintf(table, "\t%s", column)
+	}
+	fmt.Fprint(table, "\tTotal\n")
+
+	for _, row := range m.Rows {
+		fmt.Fprint(table, row)
+		for _, column := range m.Columns {
+			fmt.Fprintf(table, "\t%s", m.Cell(row, column))
+		}
+		fmt.Fprintf(table, "\t%s\n", m.Row(row))
+	}
+
+	fmt.Fprint(table, "Total")
+	for _, column := range m.Columns {
+		fmt.Fprintf(table, "\t%s", m.Column(column))
+	}
+	fmt.Fprintf(table, "\t%s\n", m.Total)
+
+	if err := table.Flush(); err != nil {
+		return fmt.Errorf("failed to write win rates: %w", err)
+	}
+
+	return nil
+}
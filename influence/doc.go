@@ -0,0 +1,5 @@
+// influence maps the threat of enemies over the game grid. Each cell holds the
+// damage per second that enemies can deal there, separately for ground and air
+// units, so positioning can avoid dangerous areas instead of relying on
+// straight-line distances.
+package influence
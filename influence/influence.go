@@ -0,0 +1,243 @@
+package influence
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/aiseeq/s2l/lib/point"
+)
+
+// Layer is the kind of unit that's threatened.
+type Layer int
+
+const (
+	Ground Layer = iota
+	Air
+)
+
+// threatCost is how many cells a path is willing to walk around to avoid a
+// cell where enemies deal one damage per second.
+const threatCost = 1
+
+// Map is the damage per second that enemies can deal on each cell of the map.
+type Map struct {
+	width  int
+	height int
+
+	layers [2][]float64
+}
+
+// New creates a map without any threat.
+func New(width int, height int) *Map {
+	return &Map{
+		width:  width,
+		height: height,
+		layers: [2][]float64{
+			make([]float64, width*height),
+			make([]float64, width*height),
+		},
+	}
+}
+
+// Reset removes every threat from the map so it can be filled again without
+// allocating new layers.
+func (m *Map) Reset() {
+	clear(m.layers[Ground])
+	clear(m.layers[Air])
+}
+
+// Add adds damage per second to every cell whose center is in a circle.
+func (m *Map) Add(layer Layer, center point.Point, radius float64, dps float64) {
+	if dps <= 0 || radius <= 0 {
+		return
+	}
+
+	minX := max(0, int(math.Floor(center.X()-radius)))
+	maxX := min(m.width-1, int(math.Ceil(center.X()+radius)))
+	minY := max(0, int(math.Floor(center.Y()-radius)))
+	maxY := min(m.height-1, int(math.Ceil(center.Y()+radius)))
+
+	cells := m.layers[layer]
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			if cellCenter(x, y).Dist(center) <= radius {
+				cells[x+y*m.width] += dps
+			}
+		}
+	}
+}
+
+// Threat is the damage per second that enemies can deal at a position. It's 0
+// outside of the map.
+func (m *Map) Threat(layer Layer, pos point.Point) float64 {
+	x, y, ok := m.cell(pos)
+	if !ok {
+		return 0
+	}
+
+	return m.layers[layer][x+y*m.width]
+}
+
+// IsSafe tells if no enemy can attack at a position.
+func (m *Map) IsSafe(layer Layer, pos point.Point) bool {
+	return m.Threat(layer, pos) == 0
+}
+
+// Path finds the path between two positions that goes through the least
+// threat. Longer paths are preferred when they avoid enough damage. It returns
+// the centers of the cells to go through, or nil when the destination can't be
+// reached. When pathable is nil, every cell can be crossed.
+func (m *Map) Path(layer Layer, from point.Point, to point.Point, pathable func(point.Pointer) bool) point.Points {
+	fromX, fromY, ok := m.cell(from)
+	if !ok {
+		return nil
+	}
+
+	toX, toY, ok := m.cell(to)
+	if !ok {
+		return nil
+	}
+
+	start, goal := fromX+fromY*m.width, toX+toY*m.width
+	goalCenter := cellCenter(toX, toY)
+
+	costs := map[int]float64{start: 0}
+	previous := map[int]int{}
+	open := &queue{{cell: start, priority: cellCenter(fromX, fromY).Dist(goalCenter)}}
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(item)
+		if current.cell == goal {
+			return m.walk(previous, start, goal)
+		}
+
+		x, y := current.cell%m.width, current.cell/m.width
+		for _, next := range m.neighbours(x, y, pathable) {
+			nextX, nextY := next%m.width, next/m.width
+			step := cellCenter(x, y).Dist(cellCenter(nextX, nextY))
+			cost := costs[current.cell] + step*(1+m.layers[layer][next]*threatCost)
+
+			if known, ok := costs[next]; ok && known <= cost {
+				continue
+			}
+
+			costs[next] = cost
+			previous[next] = current.cell
+			heap.Push(open, item{cell: next, priority: cost + cellCenter(nextX, nextY).Dist(goalCenter)})
+		}
+	}
+
+	return nil
+}
+
+// Retreat finds the closest safe position that can be reached from a position
+// without going further than a distance. When pathable is nil, every cell can
+// be crossed.
+func (m *Map) Retreat(layer Layer, from point.Point, distance float64, pathable func(point.Pointer) bool) (point.Point, bool) {
+	fromX, fromY, ok := m.cell(from)
+	if !ok {
+		return 0, false
+	}
+
+	start := fromX + fromY*m.width
+	visited := map[int]bool{start: true}
+	frontier := []int{start}
+
+	for len(frontier) > 0 {
+		current := frontier[0]
+		frontier = frontier[1:]
+
+		x, y := current%m.width, current/m.width
+		if m.layers[layer][current] == 0 {
+			return cellCenter(x, y), true
+		}
+
+		for _, next := range m.neighbours(x, y, pathable) {
+			if visited[next] || cellCenter(next%m.width, next/m.width).Dist(from) > distance {
+				continue
+			}
+
+			visited[next] = true
+			frontier = append(frontier, next)
+		}
+	}
+
+	return 0, false
+}
+
+// cell finds the coordinates of the cell at a position.
+func (m *Map) cell(pos point.Point) (int, int, bool) {
+	x, y := int(math.Floor(pos.X())), int(math.Floor(pos.Y()))
+	if x < 0 || y < 0 || x >= m.width || y >= m.height {
+		return 0, 0, false
+	}
+
+	return x, y, true
+}
+
+// neighbours lists the cells around a cell that can be crossed. Diagonals are
+// only allowed when both cells next to them can be crossed too, so paths don't
+// cut corners.
+func (m *Map) neighbours(x int, y int, pathable func(point.Pointer) bool) []int {
+	crossable := func(x, y int) bool {
+		if x < 0 || y < 0 || x >= m.width || y >= m.height {
+			return false
+		}
+
+		return pathable == nil || pathable(cellCenter(x, y))
+	}
+
+	cells := make([]int, 0, 8)
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if dx == 0 && dy == 0 || !crossable(x+dx, y+dy) {
+				continue
+			}
+
+			if dx != 0 && dy != 0 && (!crossable(x+dx, y) || !crossable(x, y+dy)) {
+				continue
+			}
+
+			cells = append(cells, x+dx+(y+dy)*m.width)
+		}
+	}
+
+	return cells
+}
+
+// walk follows a path backwards from its goal to its start.
+func (m *Map) walk(previous map[int]int, start int, goal int) point.Points {
+	var path point.Points
+	for cell := goal; ; cell = previous[cell] {
+		path = append(point.Points{cellCenter(cell%m.width, cell/m.width)}, path...)
+		if cell == start {
+			return path
+		}
+	}
+}
+
+// cellCenter is the position at the center of a cell.
+func cellCenter(x int, y int) point.Point {
+	return point.Pt(float64(x)+0.5, float64(y)+0.5)
+}
+
+// item is a cell waiting to be explored by a path search.
+type item struct {
+	cell     int
+	priority float64
+}
+
+// queue is a priority queue of cells, lowest priority first.
+type queue []item
+
+func (q queue) Len() int           { return len(q) }
+func (q queue) Less(i, j int) bool { return q[i].priority < q[j].priority }
+func (q queue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x any)        { *q = append(*q, x.(item)) }
+
+func (q *queue) Pop() any {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,117 @@
+package influence_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/influence"
+	"github.com/aiseeq/s2l/lib/point"
+)
+
+func TestMap_Add(t *testing.T) {
+	m := influence.New(32, 32)
+	m.Add(influence.Ground, point.Pt(10, 10), 3, 10)
+	m.Add(influence.Ground, point.Pt(11, 10), 3, 5)
+
+	if got := m.Threat(influence.Ground, point.Pt(10.5, 10.5)); got != 15 {
+		t.Errorf("Threat(Ground, center) = %v, expected %v", got, 15)
+	}
+
+	if got := m.Threat(influence.Ground, point.Pt(20, 20)); got != 0 {
+		t.Errorf("Threat(Ground, far) = %v, expected %v", got, 0)
+	}
+
+	if got := m.Threat(influence.Air, point.Pt(10.5, 10.5)); got != 0 {
+		t.Errorf("Threat(Air, center) = %v, expected %v", got, 0)
+	}
+
+	if got := m.Threat(influence.Ground, point.Pt(-5, 40)); got != 0 {
+		t.Errorf("Threat(Ground, outside) = %v, expected %v", got, 0)
+	}
+}
+
+func TestMap_Reset(t *testing.T) {
+	m := influence.New(32, 32)
+	m.Add(influence.Ground, point.Pt(10, 10), 3, 10)
+	m.Add(influence.Air, point.Pt(10, 10), 3, 10)
+	m.Reset()
+
+	if got := m.Threat(influence.Ground, point.Pt(10.5, 10.5)); got != 0 {
+		t.Errorf("Threat(Ground, center) = %v, expected %v", got, 0)
+	}
+
+	if got := m.Threat(influence.Air, point.Pt(10.5, 10.5)); got != 0 {
+		t.Errorf("Threat(Air, center) = %v, expected %v", got, 0)
+	}
+}
+
+func TestMap_IsSafe(t *testing.T) {
+	m := influence.New(32, 32)
+	m.Add(influence.Air, point.Pt(10, 10), 7, 12)
+
+	if m.IsSafe(influence.Air, point.Pt(12, 12)) {
+		t.Errorf("IsSafe(Air, in range) = %v, expected %v", true, false)
+	}
+
+	if !m.IsSafe(influence.Ground, point.Pt(12, 12)) {
+		t.Errorf("IsSafe(Ground, in range) = %v, expected %v", false, true)
+	}
+
+	if !m.IsSafe(influence.Air, point.Pt(25, 25)) {
+		t.Errorf("IsSafe(Air, out of range) = %v, expected %v", false, true)
+	}
+}
+
+func TestMap_PathAvoidsThreat(t *testing.T) {
+	m := influence.New(32, 32)
+	m.Add(influence.Ground, point.Pt(16, 16), 4, 20)
+
+	path := m.Path(influence.Ground, point.Pt(4.5, 16.5), point.Pt(28.5, 16.5), nil)
+	if len(path) == 0 {
+		t.Fatalf("len(Path()) = %d, expected more than 0", len(path))
+	}
+
+	if last := path[len(path)-1]; last != point.Pt(28.5, 16.5) {
+		t.Errorf("Path()[last] = %v, expected %v", last, point.Pt(28.5, 16.5))
+	}
+
+	for _, p := range path {
+		if !m.IsSafe(influence.Ground, p) {
+			t.Errorf("Path() goes through %v, expected only safe cells", p)
+		}
+	}
+}
+
+func TestMap_PathUnreachable(t *testing.T) {
+	m := influence.New(32, 32)
+
+	// A wall splits the map in two
+	pathable := func(p point.Pointer) bool {
+		return int(p.Point().X()) != 16
+	}
+
+	if path := m.Path(influence.Ground, point.Pt(4.5, 4.5), point.Pt(28.5, 4.5), pathable); path != nil {
+		t.Errorf("Path() = %v, expected %v", path, nil)
+	}
+}
+
+func TestMap_Retreat(t *testing.T) {
+	m := influence.New(32, 32)
+	m.Add(influence.Ground, point.Pt(16, 16), 4, 20)
+
+	pos, ok := m.Retreat(influence.Ground, point.Pt(17.5, 16.5), 10, nil)
+	if !ok {
+		t.Fatalf("Retreat() ok = %v, expected %v", ok, true)
+	}
+
+	if !m.IsSafe(influence.Ground, pos) {
+		t.Errorf("Retreat() = %v, expected a safe position", pos)
+	}
+
+	if dist := pos.Dist(point.Pt(17.5, 16.5)); dist > 5 {
+		t.Errorf("Retreat() is %v away, expected at most %v", dist, 5)
+	}
+
+	if _, ok := m.Retreat(influence.Ground, point.Pt(17.5, 16.5), 1, nil); ok {
+		t.Errorf("Retreat() ok = %v, expected %v", ok, false)
+	}
+}
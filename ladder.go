@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/client"
+)
+
+// JoinLadder connects to a game hosted by a ladder manager and joins it. The
+// ladder manager has already launched StarCraft II at the game port.
+func JoinLadder(flags Flags) (*client.Client, error) {
+	c := &client.Client{}
+	if err := c.Connect(flags.LadderServer, flags.GamePort, flags.Timeout); err != nil {
+		return nil, fmt.Errorf("failed to connect to %s:%d: %w", flags.LadderServer, flags.GamePort, err)
+	}
+
+	// The game advances by itself in real-time
+	c.Realtime = flags.Realtime
+
+	bot := client.NewParticipant(api.Race_Terran, "BlackCompany")
+	if err := c.RequestJoinGame(bot, interfaceOptions, LadderPorts(flags.StartPort)); err != nil {
+		return nil, fmt.Errorf("failed to join the game: %w", err)
+	}
+
+	return c, nil
+}
+
+// LadderPorts are the ports the players use to talk to each other. They come
+// after the start port, like in s2l's `SetupPorts`.
+func LadderPorts(startPort int) client.Ports {
+	ports := client.Ports{
+		SharedPort: int32(startPort + 1),
+		ServerPorts: &api.PortSet{
+			GamePort: int32(startPort + 2),
+			BasePort: int32(startPort + 3),
+		},
+	}
+
+	for i := range 2 {
+		base := int32(startPort + 4 + i*2)
+		ports.ClientPorts = append(ports.ClientPorts, &api.PortSet{GamePort: base, BasePort: base + 1})
+	}
+
+	return ports
+}
@@ -0,0 +1,86 @@
+package main_test
+
+import (
+	"testing"
+	"time"
+
+	main "github.com/NatoBoram/BlackCompany"
+	"github.com/NatoBoram/BlackCompany/agent"
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+func TestParseArg_LadderGamePort(t *testing.T) {
+	advance, name, value := main.ParseArg([]string{"--GamePort", "5677", "--RealTime"}, 0)
+
+	if advance != 1 {
+		t.Errorf("Expected advance to be 1, got %d", advance)
+	}
+	if name != "GamePort" {
+		t.Errorf("Expected name to be \"GamePort\", got %q", name)
+	}
+	if value != "5677" {
+		t.Errorf("Expected value to be \"5677\", got %q", value)
+	}
+}
+
+func TestLadderPorts(t *testing.T) {
+	ports := main.LadderPorts(5000)
+
+	if ports.SharedPort != 5001 {
+		t.Errorf("SharedPort = %d, expected %d", ports.SharedPort, 5001)
+	}
+
+	if ports.ServerPorts.GamePort != 5002 || ports.ServerPorts.BasePort != 5003 {
+		t.Errorf("ServerPorts = %v, expected %v", ports.ServerPorts, "5002, 5003")
+	}
+
+	if len(ports.ClientPorts) != 2 {
+		t.Fatalf("len(ClientPorts) = %d, expected %d", len(ports.ClientPorts), 2)
+	}
+
+	if ports.ClientPorts[1].GamePort != 5006 || ports.ClientPorts[1].BasePort != 5007 {
+		t.Errorf("ClientPorts[1] = %v, expected %v", ports.ClientPorts[1], "5006, 5007")
+	}
+}
+
+func TestJoinLadder(t *testing.T) {
+	s := sim.NewScenario(api.Race_Protoss)
+	server := sim.NewServer(s.Info, sim.Data(), s.Frames(2))
+	defer server.Close()
+
+	host, port, err := server.Start()
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	c, err := main.JoinLadder(main.Flags{
+		LadderServer: host,
+		GamePort:     port,
+		StartPort:    5000,
+		Timeout:      time.Second,
+	})
+	if err != nil {
+		t.Fatalf("JoinLadder() error = %v", err)
+	}
+
+	joins := server.Joins()
+	if len(joins) != 1 {
+		t.Fatalf("len(Joins()) = %d, expected %d", len(joins), 1)
+	}
+
+	if race := joins[0].GetRace(); race != api.Race_Terran {
+		t.Errorf("Race = %v, expected %v", race, api.Race_Terran)
+	}
+
+	if joins[0].SharedPort != 5001 {
+		t.Errorf("SharedPort = %d, expected %d", joins[0].SharedPort, 5001)
+	}
+
+	game := agent.Run(bot.New(c), agent.Fixed(&macro.Standard), nil)
+	if game.Loops == 0 {
+		t.Errorf("Loops = %d, expected the game to be played", game.Loops)
+	}
+}
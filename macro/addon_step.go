@@ -18,6 +18,7 @@ import (
 func addonStep(name string, buildingId api.UnitTypeID, addonId api.UnitTypeID, abilityId api.AbilityID, quantity int) *bot.BuildStep {
 	return &bot.BuildStep{
 		Name: stepName(name, quantity),
+		Cost: bot.AbilityCost(abilityId),
 		Predicate: func(b *bot.Bot) bool {
 			buildings := b.Units.My.OfType(buildingId).Filter(scl.Ready, scl.Ground, scl.NoAddon)
 			if buildings.Empty() {
@@ -0,0 +1,176 @@
+package macro
+
+import (
+	"math"
+	"slices"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/filter"
+	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+)
+
+// ArmyUnit is a unit type in an army composition.
+type ArmyUnit struct {
+	Unit api.UnitTypeID
+
+	// Count is how many of this unit to have. It comes before the ratios.
+	Count int
+
+	// Ratio is the weight of this unit among the other units with a ratio.
+	// These are trained forever.
+	Ratio float64
+}
+
+// Composition is the army that the production aims for.
+type Composition []ArmyUnit
+
+// producer is how a unit is trained.
+type producer struct {
+	building api.UnitTypeID
+	ability  api.AbilityID
+	techLab  bool
+}
+
+// producers are the units that army steps know how to train.
+var producers = map[api.UnitTypeID]producer{
+	terran.Marine:        {terran.Barracks, ability.Train_Marine, false},
+	terran.Reaper:        {terran.Barracks, ability.Train_Reaper, false},
+	terran.Marauder:      {terran.Barracks, ability.Train_Marauder, true},
+	terran.Hellion:       {terran.Factory, ability.Train_Hellion, false},
+	terran.SiegeTank:     {terran.Factory, ability.Train_SiegeTank, true},
+	terran.Medivac:       {terran.Starport, ability.Train_Medivac, false},
+	terran.VikingFighter: {terran.Starport, ability.Train_VikingFighter, false},
+}
+
+// reactors are the add-ons that let their building train two units at once.
+var reactors = []api.UnitTypeID{terran.BarracksReactor, terran.FactoryReactor, terran.StarportReactor}
+
+// techLabs are the add-ons that unlock the advanced units of their building.
+var techLabs = []api.UnitTypeID{terran.BarracksTechLab, terran.FactoryTechLab, terran.StarportTechLab}
+
+// armyStep trains units from barracks, factories and starports to get closer
+// to an army composition. Units with a count are trained first, then the unit
+// whose ratio is the furthest from being met. Buildings with a reactor are
+// kept busy with two units.
+func armyStep(composition Composition) *bot.BuildStep {
+	return &bot.BuildStep{
+		Name: "Train Army",
+		Predicate: func(b *bot.Bot) bool {
+			// Waiting for the minerals to catch up with the supply slows down
+			// the army production to allow for the rest of the build order to
+			// execute.
+			return b.Minerals > b.FoodUsed && b.FoodLeft > 0
+		},
+
+		Execute: func(b *bot.Bot) {
+			counts := composition.counts(b)
+			rally := b.RallyPoint()
+
+			for _, building := range productionBuildings(b, composition) {
+				slots := productionSlots(b, building) - len(building.Orders)
+				for range slots {
+					unit, ok := composition.next(b, building, counts)
+					if !ok {
+						break
+					}
+
+					train := producers[unit].ability
+					b.DeductResources(train)
+					counts[unit]++
+
+					if rally != nil {
+						building.CommandPos(ability.Rally_Building, *rally)
+					}
+
+					building.CommandQueue(train)
+					log.Info("Training %s at %v", b.U.Types[unit].Name, building.Point())
+				}
+			}
+		},
+
+		Next: func(b *bot.Bot) bool {
+			return true
+		},
+	}
+}
+
+// productionBuildings are the ready buildings that can train the units of a
+// composition. The one reserved for an add-on is left alone.
+func productionBuildings(b *bot.Bot, composition Composition) scl.Units {
+	var types []api.UnitTypeID
+	for _, unit := range composition {
+		if p, ok := producers[unit.Unit]; ok && !slices.Contains(types, p.building) {
+			types = append(types, p.building)
+		}
+	}
+
+	return b.Units.My.OfType(types...).Filter(scl.Ready, scl.Ground, filter.IsNotTag(b.State.BuildingForAddOn))
+}
+
+// productionSlots is how many units a building can train at once.
+func productionSlots(b *bot.Bot, building *scl.Unit) int {
+	addon := b.Units.ByTag[building.AddOnTag]
+	if addon != nil && addon.IsReady() && addon.Is(reactors...) {
+		return 2
+	}
+
+	return 1
+}
+
+// hasTechLab tells if a building has a finished tech lab.
+func hasTechLab(b *bot.Bot, building *scl.Unit) bool {
+	addon := b.Units.ByTag[building.AddOnTag]
+	return addon != nil && addon.IsReady() && addon.Is(techLabs...)
+}
+
+// counts are how many of each unit of a composition are alive or in
+// production.
+func (c Composition) counts(b *bot.Bot) map[api.UnitTypeID]int {
+	counts := make(map[api.UnitTypeID]int, len(c))
+	for _, unit := range c {
+		if p, ok := producers[unit.Unit]; ok {
+			counts[unit.Unit] = b.PendingAliases(p.ability)
+		}
+	}
+
+	return counts
+}
+
+// next finds the unit of the composition that a building should train next,
+// if it can afford one.
+func (c Composition) next(b *bot.Bot, building *scl.Unit, counts map[api.UnitTypeID]int) (api.UnitTypeID, bool) {
+	var best api.UnitTypeID
+	bestScore := math.Inf(1)
+
+	for _, unit := range c {
+		p, ok := producers[unit.Unit]
+		if !ok || p.building != building.UnitType || p.techLab && !hasTechLab(b, building) {
+			continue
+		}
+
+		var score float64
+		switch {
+		case unit.Count > counts[unit.Unit]:
+			// Counts come before every ratio
+			score = float64(counts[unit.Unit])/float64(unit.Count) - 1
+		case unit.Ratio > 0:
+			score = float64(counts[unit.Unit]) / unit.Ratio
+		default:
+			continue
+		}
+
+		if score < bestScore {
+			best, bestScore = unit.Unit, score
+		}
+	}
+
+	if bestScore == math.Inf(1) || !b.CanBuy(producers[best].ability) {
+		return 0, false
+	}
+
+	return best, true
+}
@@ -2,10 +2,10 @@ package macro
 
 import (
 	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/combat"
 	"github.com/NatoBoram/BlackCompany/filter"
 	"github.com/NatoBoram/BlackCompany/log"
 	"github.com/aiseeq/s2l/lib/scl"
-	"github.com/aiseeq/s2l/protocol/enums/terran"
 )
 
 // AttackWaveConfig holds the configuration for launching an attack wave.
@@ -20,60 +20,91 @@ type AttackWaveConfig struct {
 	Execute func(b *bot.Bot)
 }
 
-// firstWaveConfig puts marines into a group for launching a marine rush timing
-// attack after combat shield is started. It's never executed again after the
-// first wave is launched.
-func firstWaveConfig() *AttackWaveConfig {
-	launched := false
-
+// firstWaveConfig puts the army into a group for launching a marine rush timing
+// attack after combat shield is started. It's never executed again once a game
+// launched a quantity of first waves. Marines stay home while the enemy is
+// recognized as rushing.
+func firstWaveConfig(quantity int) *AttackWaveConfig {
 	return &AttackWaveConfig{
-		Name: "First Attack Wave",
+		Name: stepName("First Attack Wave", quantity),
 		Predicate: func(b *bot.Bot) bool {
-			return !launched
+			return b.State.FirstWaves < quantity && !expectsRush(b)
 		},
 		Execute: func(b *bot.Bot) {
-			if launched {
+			if b.State.FirstWaves >= quantity {
 				return
 			}
 
 			inWaves := b.State.AttackWaves.Units(b)
-			marines := b.Units.My.OfType(terran.Marine).Filter(scl.Ready, filter.NotIn(inWaves))
-			if marines.Empty() {
+			units := b.FindArmy().Filter(scl.Ready, filter.NotIn(inWaves))
+			if units.Empty() {
 				return
 			}
 
 			wave := bot.AttackWave{
-				Tags:   marines.Tags(),
+				Tags:   units.Tags(),
 				Target: b.Locs.EnemyStart,
 			}
 			b.State.AttackWaves = append(b.State.AttackWaves, wave)
 
-			launched = true
-			log.Info("Sending %d marines to enemy base %v", marines.Len(), b.Locs.EnemyStart)
+			b.State.FirstWaves++
+			log.Info("Sending %d units to enemy base %v", units.Len(), b.Locs.EnemyStart)
 		},
 	}
 }
 
+// fullSupplyWaveConfig puts the army into a group when the supply is maxed out.
+// It waits until the army is predicted to win against the known enemy army.
 func fullSupplyWaveConfig() *AttackWaveConfig {
 	return &AttackWaveConfig{
 		Name: "Full Supply Attack Wave",
 		Predicate: func(b *bot.Bot) bool {
-			marines := b.Units.My.OfType(terran.Marine).Filter(scl.Ready, filter.NotIn(b.State.AttackWaves.Units(b)))
-			return b.Obs.PlayerCommon.FoodUsed >= b.Obs.PlayerCommon.FoodCap && marines.Len() >= 30
+			units := b.FindArmy().Filter(scl.Ready, filter.NotIn(b.State.AttackWaves.Units(b)))
+			if b.Obs.PlayerCommon.FoodUsed < b.Obs.PlayerCommon.FoodCap || units.Len() < 30 {
+				return false
+			}
+
+			army := b.State.EnemyMemory.Army()
+			return army.Empty() || b.Simulate(units, army).Winner == combat.Ally
 		},
 		Execute: func(b *bot.Bot) {
-			marines := b.Units.My.OfType(terran.Marine).Filter(scl.Ready, filter.NotIn(b.State.AttackWaves.Units(b)))
-			if marines.Empty() {
+			units := b.FindArmy().Filter(scl.Ready, filter.NotIn(b.State.AttackWaves.Units(b)))
+			if units.Empty() {
 				return
 			}
 
 			wave := bot.AttackWave{
-				Tags:   marines.Tags(),
-				Target: marines.Center(),
+				Tags:   units.Tags(),
+				Target: units.Center(),
 			}
 
 			b.State.AttackWaves = append(b.State.AttackWaves, wave)
-			log.Info("Preparing new attack wave with %d units", marines.Len())
+			log.Info("Preparing new attack wave with %d units", units.Len())
 		},
 	}
 }
+
+// rushWindow is how long an early attack is expected to last.
+const rushWindow = 4 * 60 * scl.FPS
+
+// expectsRush tells if the enemy's opening is an early attack that marines
+// should defend against. The rush is held once its window is over and no enemy
+// is left in the bases.
+func expectsRush(b *bot.Bot) bool {
+	opening := b.State.EnemyOpening
+	if !opening.Opening.Aggressive() || opening.Confidence < 0.5 {
+		return false
+	}
+
+	if b.Loop < int(rushWindow) {
+		return true
+	}
+
+	for _, enemies := range b.FindEnemiesInBases() {
+		if enemies.Filter(func(u *scl.Unit) bool { return !u.IsWorker() }).Exists() {
+			return true
+		}
+	}
+
+	return false
+}
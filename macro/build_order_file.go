@@ -0,0 +1,462 @@
+package macro
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/unit"
+	"gopkg.in/yaml.v3"
+)
+
+// StrategyFile describes a strategy in a YAML or JSON file.
+type StrategyFile struct {
+	// Name of the strategy, as selected with `-strategy`.
+	Name string `json:"name" yaml:"name"`
+
+	// EnemyRaces are the races this strategy is meant to play against, like
+	// "Zerg" or "Protoss". When it's empty, the strategy is meant for any race.
+	EnemyRaces []string `json:"enemyRaces" yaml:"enemyRaces"`
+
+	// Parallel lets the steps after a blocked step run as long as they don't
+	// need the resources the blocked step is saving for.
+	Parallel bool `json:"parallel,omitempty" yaml:"parallel,omitempty"`
+
+	// Steps are the build order.
+	Steps []StepFile `json:"steps" yaml:"steps"`
+}
+
+// StepFile describes a build step in a YAML or JSON file.
+type StepFile struct {
+	// Kind is the type of step. See `stepKinds` for the supported kinds.
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Name overrides the name of the step in logs.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Unit is the structure or add-on to build, like "Barracks" or
+	// "BarracksReactor".
+	Unit string `json:"unit,omitempty" yaml:"unit,omitempty"`
+
+	// Building is the structure that builds an add-on or researches an upgrade,
+	// like "Barracks" or "EngineeringBay".
+	Building string `json:"building,omitempty" yaml:"building,omitempty"`
+
+	// Ability is the ability used by the step, like "Research_Stimpack". For
+	// structures and add-ons, it's found from the unit when it's empty.
+	Ability string `json:"ability,omitempty" yaml:"ability,omitempty"`
+
+	// Quantity is how many of this unit to have. 0 means that it's repeated
+	// forever.
+	Quantity int `json:"quantity,omitempty" yaml:"quantity,omitempty"`
+
+	// Requires are the structures that must be finished before this step.
+	Requires []string `json:"requires,omitempty" yaml:"requires,omitempty"`
+
+	// Supply is the supply at which this step starts.
+	Supply int `json:"supply,omitempty" yaml:"supply,omitempty"`
+
+	// Time is the game time at which this step starts, like "1m30s".
+	Time string `json:"time,omitempty" yaml:"time,omitempty"`
+
+	// Wave is the configuration of an attack wave, either "first" or
+	// "fullSupply".
+	Wave string `json:"wave,omitempty" yaml:"wave,omitempty"`
+
+	// Army is the composition trained by an army step.
+	Army []ArmyUnitFile `json:"army,omitempty" yaml:"army,omitempty"`
+}
+
+// ArmyUnitFile describes a unit of an army composition in a YAML or JSON file.
+type ArmyUnitFile struct {
+	// Unit is the unit to train, like "Marine" or "SiegeTank".
+	Unit string `json:"unit" yaml:"unit"`
+
+	// Count is how many of this unit to have before following the ratios.
+	Count int `json:"count,omitempty" yaml:"count,omitempty"`
+
+	// Ratio is the weight of this unit among the other units with a ratio.
+	Ratio float64 `json:"ratio,omitempty" yaml:"ratio,omitempty"`
+}
+
+// stepKinds creates the build steps that can be used in files.
+var stepKinds = map[string]func(s StepFile) (*bot.BuildStep, error){
+	"defenseWave":       func(s StepFile) (*bot.BuildStep, error) { return &defenseWaveStep, nil },
+	"supplyDepot":       func(s StepFile) (*bot.BuildStep, error) { return &supplyDepotStep, nil },
+	"chatVersion":       func(s StepFile) (*bot.BuildStep, error) { return chatVersionStep(), nil },
+	"marine":            func(s StepFile) (*bot.BuildStep, error) { return &marineStep, nil },
+	"planetaryFortress": func(s StepFile) (*bot.BuildStep, error) { return &planetaryFortressStep, nil },
+	"turret":            func(s StepFile) (*bot.BuildStep, error) { return &turretStep, nil },
+	"refinery":          func(s StepFile) (*bot.BuildStep, error) { return refineryStep(s.Quantity), nil },
+	"orbitalCommand":    func(s StepFile) (*bot.BuildStep, error) { return orbitalCommandStep(s.Quantity), nil },
+	"expand":            func(s StepFile) (*bot.BuildStep, error) { return expandStep(s.Quantity), nil },
+	"building":          buildingStepFile,
+	"addon":             addonStepFile,
+	"upgrade":           upgradeStepFile,
+	"attackWave":        attackWaveStepFile,
+	"army":              armyStepFile,
+	"harass":            func(s StepFile) (*bot.BuildStep, error) { return harassStep(), nil },
+}
+
+// LoadStrategy reads a strategy from a YAML or JSON file. The format is chosen
+// from the file's extension.
+func LoadStrategy(path string) (*bot.Strategy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read strategy file %q: %w", path, err)
+	}
+
+	var file StrategyFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&file)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&file)
+	default:
+		return nil, fmt.Errorf("unsupported strategy file %q, expected a .json, .yaml or .yml file", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse strategy file %q: %w", path, err)
+	}
+
+	strategy, err := file.Strategy()
+	if err != nil {
+		return nil, fmt.Errorf("invalid strategy file %q: %w", path, err)
+	}
+
+	return strategy, nil
+}
+
+// Save writes the strategy to a YAML or JSON file so it can be loaded with
+// `LoadStrategy`. The format is chosen from the file's extension.
+func (f StrategyFile) Save(path string) error {
+	var buffer bytes.Buffer
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		encoder := json.NewEncoder(&buffer)
+		encoder.SetIndent("", "\t")
+		if err := encoder.Encode(f); err != nil {
+			return fmt.Errorf("failed to encode strategy as JSON: %w", err)
+		}
+	case ".yaml", ".yml":
+		encoder := yaml.NewEncoder(&buffer)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(f); err != nil {
+			return fmt.Errorf("failed to encode strategy as YAML: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return fmt.Errorf("failed to encode strategy as YAML: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported strategy file %q, expected a .json, .yaml or .yml file", path)
+	}
+
+	if err := os.WriteFile(path, buffer.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write strategy file %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// Strategy validates the file then turns it into a strategy. Every validation
+// error is reported at once.
+func (f StrategyFile) Strategy() (*bot.Strategy, error) {
+	var errs []error
+
+	if f.Name == "" {
+		errs = append(errs, fmt.Errorf("missing name"))
+	}
+
+	races := make([]api.Race, 0, len(f.EnemyRaces))
+	for _, name := range f.EnemyRaces {
+		race, ok := api.Race_value[name]
+		if !ok || api.Race(race) == api.Race_NoRace {
+			errs = append(errs, fmt.Errorf("unknown enemy race %q", name))
+			continue
+		}
+
+		races = append(races, api.Race(race))
+	}
+
+	if len(f.Steps) == 0 {
+		errs = append(errs, fmt.Errorf("missing steps"))
+	}
+
+	steps := make(bot.BuildOrder, 0, len(f.Steps))
+	for i, s := range f.Steps {
+		step, err := s.step()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("step %d (%s): %w", i+1, s.Kind, err))
+			continue
+		}
+
+		steps = append(steps, step)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return &bot.Strategy{Name: f.Name, Steps: steps, Parallel: f.Parallel, EnemyRaces: races}, nil
+}
+
+// step turns a step from a file into a build step.
+func (s StepFile) step() (*bot.BuildStep, error) {
+	create, ok := stepKinds[s.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown kind %q", s.Kind)
+	}
+
+	if s.Quantity < 0 {
+		return nil, fmt.Errorf("negative quantity %d", s.Quantity)
+	}
+
+	if s.Supply < 0 || s.Supply > 200 {
+		return nil, fmt.Errorf("supply %d is not between 0 and 200", s.Supply)
+	}
+
+	var loop int
+	if s.Time != "" {
+		d, err := time.ParseDuration(s.Time)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid time %q, expected a duration like \"1m30s\"", s.Time)
+		}
+
+		loop = int(d.Seconds() * scl.FPS)
+	}
+
+	step, err := create(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Name != "" || s.Supply > 0 || loop > 0 {
+		step = triggeredStep(step, s.Name, s.Supply, loop)
+	}
+
+	return step, nil
+}
+
+// triggeredStep wraps a build step so that it only starts once the supply and
+// game loop are reached. It can also rename the step.
+func triggeredStep(step *bot.BuildStep, name string, supply int, loop int) *bot.BuildStep {
+	if name == "" {
+		name = step.Name
+	}
+
+	triggered := func(b *bot.Bot) bool {
+		return b.FoodUsed >= supply && b.Loop >= loop
+	}
+
+	return &bot.BuildStep{
+		Name: name,
+		Predicate: func(b *bot.Bot) bool {
+			return triggered(b) && step.Predicate(b)
+		},
+		Execute: step.Execute,
+		Next: func(b *bot.Bot) bool {
+			return triggered(b) && step.Next(b)
+		},
+		Cost: step.Cost,
+	}
+}
+
+func buildingStepFile(s StepFile) (*bot.BuildStep, error) {
+	building, err := findUnit(s.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	abilityId, err := findAbilityOr(s.Ability, "Build_"+unitName(building))
+	if err != nil {
+		return nil, err
+	}
+
+	requirements := make([]api.UnitTypeID, 0, len(s.Requires))
+	for _, name := range s.Requires {
+		requirement, err := findUnit(name)
+		if err != nil {
+			return nil, err
+		}
+
+		requirements = append(requirements, requirement)
+	}
+
+	return buildingStep(unitName(building), building, abilityId, s.Quantity, requirements...), nil
+}
+
+func addonStepFile(s StepFile) (*bot.BuildStep, error) {
+	building, err := findUnit(s.Building)
+	if err != nil {
+		return nil, err
+	}
+
+	addon, err := findUnit(s.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add-ons are named like "BarracksReactor" and built with abilities named
+	// like "Build_Reactor_Barracks".
+	kind := strings.TrimPrefix(unitName(addon), unitName(building))
+	abilityId, err := findAbilityOr(s.Ability, "Build_"+kind+"_"+unitName(building))
+	if err != nil {
+		return nil, err
+	}
+
+	return addonStep(unitName(addon), building, addon, abilityId, s.Quantity), nil
+}
+
+func upgradeStepFile(s StepFile) (*bot.BuildStep, error) {
+	building, err := findUnit(s.Building)
+	if err != nil {
+		return nil, err
+	}
+
+	abilityId, err := findAbility(s.Ability)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimPrefix(s.Ability, "Research_")
+	return upgradeStep(name, abilityId, building), nil
+}
+
+func attackWaveStepFile(s StepFile) (*bot.BuildStep, error) {
+	switch s.Wave {
+	case "first":
+		// Each first wave is launched once, so there's at least one
+		return attackWaveStep(firstWaveConfig(max(s.Quantity, 1))), nil
+	case "fullSupply":
+		return attackWaveStep(fullSupplyWaveConfig()), nil
+	default:
+		return nil, fmt.Errorf("unknown wave %q, expected \"first\" or \"fullSupply\"", s.Wave)
+	}
+}
+
+func armyStepFile(s StepFile) (*bot.BuildStep, error) {
+	if len(s.Army) == 0 {
+		return nil, fmt.Errorf("missing army")
+	}
+
+	composition := make(Composition, 0, len(s.Army))
+	for _, u := range s.Army {
+		id, err := findUnit(u.Unit)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := producers[id]; !ok {
+			return nil, fmt.Errorf("unit %q can't be trained by an army step", u.Unit)
+		}
+
+		if u.Count < 0 || u.Ratio < 0 {
+			return nil, fmt.Errorf("negative count or ratio for unit %q", u.Unit)
+		}
+
+		composition = append(composition, ArmyUnit{Unit: id, Count: u.Count, Ratio: u.Ratio})
+	}
+
+	return armyStep(composition), nil
+}
+
+var (
+	namesOnce sync.Once
+	units     map[string]api.UnitTypeID
+	abilities map[string]api.AbilityID
+)
+
+// loadNames indexes unit types and abilities by their names.
+func loadNames() {
+	units = make(map[string]api.UnitTypeID)
+	for id := api.UnitTypeID(1); id < 5000; id++ {
+		name := unit.String(id)
+		if name == "" {
+			continue
+		}
+
+		units[name] = id
+
+		// Units are also known without their race, like "Barracks"
+		if _, short, ok := strings.Cut(name, "_"); ok {
+			if _, exists := units[short]; !exists {
+				units[short] = id
+			}
+		}
+	}
+
+	abilities = make(map[string]api.AbilityID)
+	for id := api.AbilityID(1); id < 5000; id++ {
+		if name := ability.String(id); name != "" {
+			abilities[name] = id
+		}
+	}
+}
+
+// findUnit finds a unit type by its name, like "Barracks" or
+// "Terran_Barracks".
+func findUnit(name string) (api.UnitTypeID, error) {
+	namesOnce.Do(loadNames)
+
+	if name == "" {
+		return 0, fmt.Errorf("missing unit name")
+	}
+
+	id, ok := units[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", name)
+	}
+
+	return id, nil
+}
+
+// findAbility finds an ability by its name, like "Research_Stimpack".
+func findAbility(name string) (api.AbilityID, error) {
+	namesOnce.Do(loadNames)
+
+	if name == "" {
+		return 0, fmt.Errorf("missing ability name")
+	}
+
+	id, ok := abilities[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown ability %q", name)
+	}
+
+	return id, nil
+}
+
+// findAbilityOr finds an ability by its name, or by a fallback name when the
+// name is empty.
+func findAbilityOr(name string, fallback string) (api.AbilityID, error) {
+	if name == "" {
+		return findAbility(fallback)
+	}
+
+	return findAbility(name)
+}
+
+// unitName is the name of a unit type without its race, like "Barracks".
+func unitName(id api.UnitTypeID) string {
+	name := unit.String(id)
+	if _, short, ok := strings.Cut(name, "_"); ok {
+		return short
+	}
+
+	return name
+}
@@ -0,0 +1,150 @@
+package macro_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+func TestLoadStrategy_Example(t *testing.T) {
+	strategy, err := macro.LoadStrategy(filepath.Join("..", "strategies", "three_rax.yaml"))
+	if err != nil {
+		t.Fatalf("LoadStrategy() error = %v", err)
+	}
+
+	if strategy.Name != "ThreeRax" {
+		t.Errorf("Name = %q, expected %q", strategy.Name, "ThreeRax")
+	}
+
+	if len(strategy.Steps) != 19 {
+		t.Errorf("len(Steps) = %d, expected %d", len(strategy.Steps), 19)
+	}
+
+	if !strategy.Parallel {
+		t.Errorf("Parallel = %v, expected %v", strategy.Parallel, true)
+	}
+
+	if strategy.IsMeantFor(api.Race_Terran) {
+		t.Errorf("IsMeantFor(Terran) = %v, expected %v", true, false)
+	}
+
+	if name := strategy.Steps[6].Name; name != "Barracks x3" {
+		t.Errorf("Steps[6].Name = %q, expected %q", name, "Barracks x3")
+	}
+}
+
+func TestLoadStrategy_JSON(t *testing.T) {
+	path := writeStrategy(t, "strategy.json", `{
+		"name": "Json",
+		"steps": [
+			{"kind": "building", "unit": "Terran_Factory", "quantity": 1, "requires": ["Barracks"]},
+			{"kind": "addon", "unit": "FactoryTechLab", "building": "Factory", "quantity": 1}
+		]
+	}`)
+
+	strategy, err := macro.LoadStrategy(path)
+	if err != nil {
+		t.Fatalf("LoadStrategy() error = %v", err)
+	}
+
+	if len(strategy.Steps) != 2 {
+		t.Errorf("len(Steps) = %d, expected %d", len(strategy.Steps), 2)
+	}
+}
+
+func TestLoadStrategy_UnknownNames(t *testing.T) {
+	path := writeStrategy(t, "strategy.yaml", `
+name: Broken
+enemyRaces: [Elves]
+steps:
+  - kind: building
+    unit: Barraks
+  - kind: upgrade
+    building: BarracksTechLab
+    ability: Research_Stimpak
+  - kind: nuke
+  - kind: army
+    army:
+      - unit: SCV
+        ratio: 1
+`)
+
+	_, err := macro.LoadStrategy(path)
+	if err == nil {
+		t.Fatalf("LoadStrategy() error = %v, expected an error", err)
+	}
+
+	for _, expected := range []string{
+		`unknown enemy race "Elves"`,
+		`step 1 (building): unknown unit "Barraks"`,
+		`step 2 (upgrade): unknown ability "Research_Stimpak"`,
+		`step 3 (nuke): unknown kind "nuke"`,
+		`step 4 (army): unit "SCV" can't be trained by an army step`,
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("LoadStrategy() error = %v, expected it to contain %q", err, expected)
+		}
+	}
+}
+
+func TestLoadStrategy_UnknownField(t *testing.T) {
+	path := writeStrategy(t, "strategy.yml", `
+name: Typo
+steps:
+  - kind: building
+    unti: Barracks
+`)
+
+	if _, err := macro.LoadStrategy(path); err == nil {
+		t.Errorf("LoadStrategy() error = %v, expected an error", err)
+	}
+}
+
+func TestStrategyFile_Save(t *testing.T) {
+	file := macro.StrategyFile{
+		Name:       "Saved",
+		EnemyRaces: []string{"Zerg"},
+		Steps: []macro.StepFile{
+			{Kind: "building", Unit: "Barracks", Quantity: 1, Requires: []string{"SupplyDepot"}, Supply: 15},
+			{Kind: "marine"},
+		},
+	}
+
+	for _, name := range []string{"strategy.yaml", "strategy.json"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := file.Save(path); err != nil {
+			t.Fatalf("Save(%q) error = %v", name, err)
+		}
+
+		strategy, err := macro.LoadStrategy(path)
+		if err != nil {
+			t.Fatalf("LoadStrategy(%q) error = %v", name, err)
+		}
+
+		if strategy.Name != file.Name || len(strategy.Steps) != len(file.Steps) {
+			t.Errorf("LoadStrategy(%q) = %s with %d steps, expected %s with %d steps",
+				name, strategy.Name, len(strategy.Steps), file.Name, len(file.Steps))
+		}
+	}
+}
+
+func TestRegisterStrategy_Duplicate(t *testing.T) {
+	if err := macro.RegisterStrategy(&macro.Standard); err == nil {
+		t.Errorf("RegisterStrategy() error = %v, expected an error", err)
+	}
+}
+
+func writeStrategy(t *testing.T, name string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %q: %v", path, err)
+	}
+
+	return path
+}
@@ -10,6 +10,7 @@ import (
 func buildingStep(name string, buildingId api.UnitTypeID, abilityId api.AbilityID, quantity int, requirements ...api.UnitTypeID) *bot.BuildStep {
 	return &bot.BuildStep{
 		Name: stepName(name, quantity),
+		Cost: bot.AbilityCost(abilityId),
 		Predicate: func(b *bot.Bot) bool {
 			for _, requirement := range requirements {
 				if b.Units.My.OfType(requirement).Filter(scl.Ready).Empty() {
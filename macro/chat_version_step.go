@@ -8,17 +8,15 @@ import (
 	"github.com/aiseeq/s2l/protocol/api"
 )
 
-// chatVersionStep announces the version number of the bot.
+// chatVersionStep announces the version number of the bot once per game.
 func chatVersionStep() *bot.BuildStep {
-	announced := false
-
 	return &bot.BuildStep{
 		Name: "Announce version number",
 		Predicate: func(b *bot.Bot) bool {
 			return true
 		},
 		Execute: func(b *bot.Bot) {
-			if announced {
+			if b.State.VersionAnnounced {
 				return
 			}
 
@@ -29,7 +27,7 @@ func chatVersionStep() *bot.BuildStep {
 				b.Actions.ChatSend(message, api.ActionChat_Team)
 			}
 
-			announced = true
+			b.State.VersionAnnounced = true
 		},
 		Next: func(b *bot.Bot) bool {
 			return true
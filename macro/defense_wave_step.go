@@ -5,7 +5,6 @@ import (
 	"github.com/NatoBoram/BlackCompany/filter"
 	"github.com/NatoBoram/BlackCompany/log"
 	"github.com/aiseeq/s2l/lib/scl"
-	"github.com/aiseeq/s2l/protocol/enums/terran"
 )
 
 // defenseWaveStep assigns an attack wave to the defense of the bases.
@@ -33,17 +32,17 @@ var defenseWaveStep = bot.BuildStep{
 		}
 
 		inWaves := b.State.AttackWaves.Units(b)
-		marines := b.Units.My.OfType(terran.Marine).Filter(scl.Ready, filter.NotIn(inWaves))
-		if marines.Empty() {
+		units := b.FindArmy().Filter(scl.Ready, filter.NotIn(inWaves))
+		if units.Empty() {
 			return
 		}
 
 		wave := bot.AttackWave{
-			Tags:   marines.Tags(),
+			Tags:   units.Tags(),
 			Target: cluster.Center(),
 		}
 		b.State.AttackWaves = append(b.State.AttackWaves, wave)
-		log.Info("Sending %d marines defend base at %v", marines.Len(), base.Point())
+		log.Info("Sending %d units to defend base at %v", units.Len(), base.Point())
 	},
 	Next: func(b *bot.Bot) bool {
 		return true
@@ -11,6 +11,7 @@ import (
 func expandStep(quantity int) *bot.BuildStep {
 	return &bot.BuildStep{
 		Name: stepName("Expand", quantity),
+		Cost: bot.AbilityCost(ability.Build_CommandCenter),
 		Predicate: func(b *bot.Bot) bool {
 			if !b.ShouldExpand() {
 				return false
@@ -0,0 +1,83 @@
+package macro
+
+import (
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/filter"
+	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+)
+
+const (
+	// dropMarines is how many marines board a medivac for a drop.
+	dropMarines = 8
+
+	// minHellions and maxHellions are the sizes of hellion squads.
+	minHellions = 4
+	maxHellions = 6
+)
+
+// harassStep sends a harass squad to the enemy's mineral lines when none is out.
+// Squads are a medivac full of marines or a few hellions that aren't already in
+// an attack wave.
+func harassStep() *bot.BuildStep {
+	return &bot.BuildStep{
+		Name: "Harass",
+		Predicate: func(b *bot.Bot) bool {
+			return len(b.State.HarassSquads) == 0
+		},
+
+		Execute: func(b *bot.Bot) {
+			free := b.FindArmy().Filter(scl.Ready, filter.NotIn(b.State.AttackWaves.Units(b)))
+			units := harassUnits(free)
+			if units.Empty() {
+				return
+			}
+
+			target := b.MineralLine(harassTarget(b))
+			squad := bot.HarassSquad{Tags: units.Tags(), Target: target}
+			b.State.HarassSquads = append(b.State.HarassSquads, squad)
+			log.Info("Sending %d units to harass the mineral line at %v", units.Len(), target)
+		},
+
+		Next: func(b *bot.Bot) bool {
+			return true
+		},
+	}
+}
+
+// harassUnits picks the units of a new harass squad, a healthy medivac with the
+// marines closest to it or a group of hellions.
+func harassUnits(free scl.Units) scl.Units {
+	marines := free.OfType(terran.Marine).Filter(func(u *scl.Unit) bool { return u.Hits >= u.HitsMax })
+	medivacs := free.OfType(terran.Medivac).Filter(func(u *scl.Unit) bool { return u.Hits >= u.HitsMax })
+	if medivacs.Exists() && marines.Len() >= dropMarines {
+		medivac := medivacs.ClosestTo(marines.Center())
+		marines.OrderByDistanceTo(medivac, false)
+		return append(scl.Units{medivac}, marines[:dropMarines]...)
+	}
+
+	hellions := free.OfType(terran.Hellion)
+	if hellions.Len() >= minHellions {
+		hellions.OrderByDistanceTo(hellions.Center(), false)
+		return hellions[:min(maxHellions, hellions.Len())]
+	}
+
+	return nil
+}
+
+// harassTarget is the enemy base that's the furthest from the enemy's army.
+func harassTarget(b *bot.Bot) point.Point {
+	townHalls := b.EnemyTownHalls()
+	if townHalls.Empty() {
+		return b.Locs.EnemyStart
+	}
+
+	army := b.State.EnemyMemory.Army()
+	if army.Empty() {
+		return townHalls.ClosestTo(b.Locs.MyStart).Point()
+	}
+
+	return townHalls.FurthestTo(army.Center()).Point()
+}
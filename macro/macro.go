@@ -15,28 +15,124 @@ import (
 	"github.com/jwalton/gchalk"
 )
 
-// Step executes a strategy.
-func Step(b *bot.Bot, s *bot.Strategy, last string) string {
+// Step executes a strategy. When a trace is provided, the timing of each step
+// is recorded in it.
+func Step(b *bot.Bot, s *bot.Strategy, last string, trace *Trace) string {
 	// Skip repeated frames
 	if b.LastLoop != b.Loop {
 		return last
 	}
 
-	for _, step := range s.Steps {
-		if step.Predicate(b) {
-			step.Execute(b)
+	if s.Parallel {
+		return stepParallel(b, s, last, trace)
+	}
+
+	for i, step := range s.Steps {
+		runStep(b, step, i, trace)
+
+		if step.Next(b) {
+			trace.done(b, i)
+			continue
+		}
+
+		logStep(step.Name, last)
+		return step.Name
+	}
+
+	return last
+}
+
+// stepParallel executes a strategy without stopping at blocked steps. A blocked
+// step reserves its cost so the following steps can only spend what's left.
+// Steps that don't declare a cost still block the rest of the build order.
+func stepParallel(b *bot.Bot, s *bot.Strategy, last string, trace *Trace) string {
+	ledger := bot.Ledger{}
+	defer ledger.Release(b)
+
+	current := ""
+	for i, step := range s.Steps {
+		issued := runStep(b, step, i, trace)
+
+		if step.Next(b) {
+			trace.done(b, i)
+			continue
+		}
+
+		if current == "" {
+			current = step.Name
+		}
+
+		if step.Cost == nil {
+			break
+		}
+
+		// Steps that just spent their resources don't need to save them
+		if !issued {
+			ledger.Reserve(b, step.Cost(b))
+		}
+	}
+
+	if current == "" {
+		return last
+	}
+
+	logStep(current, last)
+	return current
+}
+
+// runStep executes a step if its predicate is true and tells if it issued
+// commands.
+func runStep(b *bot.Bot, step *bot.BuildStep, i int, trace *Trace) bool {
+	if !step.Predicate(b) {
+		return false
+	}
+
+	trace.ready(b, i)
+
+	before := pendingCommands(b)
+	step.Execute(b)
+	if pendingCommands(b) <= before {
+		return false
+	}
+
+	trace.executed(b, i)
+	return true
+}
+
+// logStep logs the current build step when it changes.
+func logStep(current string, last string) {
+	if last != "" && last != current {
+		log.Info("Current build step: %s", gchalk.Bold(current))
+	}
+}
+
+// pendingCommands counts the commands and actions that are about to be sent.
+func pendingCommands(b *bot.Bot) int {
+	count := len(b.Actions)
+
+	for _, simple := range []map[api.AbilityID]scl.Units{b.Cmds.Simple, b.Cmds.SimpleQueue} {
+		for _, units := range simple {
+			count += len(units)
 		}
+	}
 
-		if !step.Next(b) {
-			if last != "" && last != step.Name {
-				log.Info("Current build step: %s", gchalk.Bold(step.Name))
+	for _, pos := range []map[api.AbilityID]map[point.Point]scl.Units{b.Cmds.Pos, b.Cmds.PosQueue} {
+		for _, targets := range pos {
+			for _, units := range targets {
+				count += len(units)
 			}
+		}
+	}
 
-			return step.Name
+	for _, tag := range []map[api.AbilityID]map[api.UnitTag]scl.Units{b.Cmds.Tag, b.Cmds.TagQueue} {
+		for _, targets := range tag {
+			for _, units := range targets {
+				count += len(units)
+			}
 		}
 	}
 
-	return last
+	return count
 }
 
 func deductMarines(b *bot.Bot, barracks *scl.Unit) int {
@@ -61,17 +157,6 @@ func deductMarines(b *bot.Bot, barracks *scl.Unit) int {
 	return 2
 }
 
-func rallyPoint(b *bot.Bot) *point.Point {
-	townHalls := b.FindTownHalls().Filter(filter.IsCcAtExpansion(b.State.CcForExp))
-	if townHalls.Empty() {
-		return nil
-	}
-
-	closest := townHalls.ClosestTo(b.Locs.EnemyStart)
-	rally := closest.Towards(b.Locs.EnemyStart, closest.SightRange())
-	return &rally
-}
-
 func build(b *bot.Bot, name string, buildingId api.UnitTypeID, abilityId api.AbilityID, size scl.BuildingSize) {
 	if !b.CanBuy(abilityId) {
 		return
@@ -29,7 +29,7 @@ var marineStep = bot.BuildStep{
 				break
 			}
 
-			if rally := rallyPoint(b); rally != nil {
+			if rally := b.RallyPoint(); rally != nil {
 				barrack.CommandPos(ability.Rally_Building, rally)
 			}
 
@@ -14,6 +14,7 @@ import (
 func orbitalCommandStep(quantity int) *bot.BuildStep {
 	return &bot.BuildStep{
 		Name: stepName("Orbital Command", quantity),
+		Cost: bot.AbilityCost(ability.Morph_OrbitalCommand),
 		Predicate: func(b *bot.Bot) bool {
 			barracks := b.Units.My.OfType(terran.Barracks).Filter(scl.Ready, scl.Ground)
 			if barracks.Empty() {
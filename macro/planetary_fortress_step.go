@@ -15,6 +15,7 @@ import (
 // for that.
 var planetaryFortressStep = bot.BuildStep{
 	Name: "Planetary Fortress",
+	Cost: bot.AbilityCost(ability.Morph_PlanetaryFortress),
 	Predicate: func(b *bot.Bot) bool {
 		if !b.CanBuy(ability.Morph_PlanetaryFortress) {
 			return false
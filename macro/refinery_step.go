@@ -13,6 +13,7 @@ import (
 func refineryStep(quantity int) *bot.BuildStep {
 	return &bot.BuildStep{
 		Name: stepName("Refinery", quantity),
+		Cost: bot.AbilityCost(ability.Build_Refinery),
 		Predicate: func(b *bot.Bot) bool {
 			if !b.CanBuy(ability.Build_Refinery) {
 				return false
@@ -0,0 +1,77 @@
+package macro
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/wheel"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+// RandomStrategy is the name to use for picking a random strategy that's meant
+// for the enemy's race.
+const RandomStrategy = "random"
+
+// Strategies are the strategies that can be selected by name.
+var Strategies = []*bot.Strategy{
+	&Standard,
+}
+
+// FindStrategy finds a strategy by its name, ignoring case.
+func FindStrategy(name string) (*bot.Strategy, error) {
+	for _, strategy := range Strategies {
+		if strings.EqualFold(strategy.Name, name) {
+			return strategy, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown strategy %q, expected one of %s or %q",
+		name, strings.Join(StrategyNames(), ", "), RandomStrategy)
+}
+
+// RegisterStrategy adds a strategy to the ones that can be selected by name.
+func RegisterStrategy(strategy *bot.Strategy) error {
+	if existing, err := FindStrategy(strategy.Name); err == nil {
+		return fmt.Errorf("strategy %q is already registered as %q", strategy.Name, existing.Name)
+	}
+
+	if strings.EqualFold(strategy.Name, RandomStrategy) {
+		return fmt.Errorf("strategy %q can't be registered because %q is reserved", strategy.Name, RandomStrategy)
+	}
+
+	Strategies = append(Strategies, strategy)
+	return nil
+}
+
+// StrategyNames returns the names of every strategy.
+func StrategyNames() []string {
+	names := make([]string, 0, len(Strategies))
+	for _, strategy := range Strategies {
+		names = append(names, strategy.Name)
+	}
+
+	return names
+}
+
+// ChooseStrategy returns the strategy with the provided name. When the name is
+// RandomStrategy, a random strategy meant for the enemy's race is chosen
+// instead.
+func ChooseStrategy(name string, race api.Race) (*bot.Strategy, error) {
+	if !strings.EqualFold(name, RandomStrategy) {
+		return FindStrategy(name)
+	}
+
+	candidates := make([]*bot.Strategy, 0, len(Strategies))
+	for _, strategy := range Strategies {
+		if strategy.IsMeantFor(race) {
+			candidates = append(candidates, strategy)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no strategy is meant to play against %v", race)
+	}
+
+	return wheel.RandomIn(candidates), nil
+}
@@ -0,0 +1,38 @@
+package macro_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+func TestFindStrategy_IgnoresCase(t *testing.T) {
+	strategy, err := macro.FindStrategy("standard")
+	if err != nil {
+		t.Fatalf("FindStrategy() error = %v", err)
+	}
+
+	if strategy != &macro.Standard {
+		t.Errorf("FindStrategy() = %q, expected %q", strategy.Name, macro.Standard.Name)
+	}
+}
+
+func TestFindStrategy_Unknown(t *testing.T) {
+	if _, err := macro.FindStrategy("unknown"); err == nil {
+		t.Errorf("FindStrategy() error = %v, expected an error", err)
+	}
+}
+
+func TestChooseStrategy_RandomIsMeantForRace(t *testing.T) {
+	for _, race := range []api.Race{api.Race_Terran, api.Race_Zerg, api.Race_Protoss, api.Race_Random} {
+		strategy, err := macro.ChooseStrategy(macro.RandomStrategy, race)
+		if err != nil {
+			t.Fatalf("ChooseStrategy(%v) error = %v", race, err)
+		}
+
+		if !strategy.IsMeantFor(race) {
+			t.Errorf("ChooseStrategy(%v).IsMeantFor(%v) = %v, expected %v", race, race, false, true)
+		}
+	}
+}
@@ -6,6 +6,15 @@ import (
 	"github.com/aiseeq/s2l/protocol/enums/terran"
 )
 
+// standardArmy is mostly marines, with a few marauders, medivacs to heal them
+// and siege tanks.
+var standardArmy = Composition{
+	{Unit: terran.Medivac, Count: 4},
+	{Unit: terran.SiegeTank, Count: 2},
+	{Unit: terran.Marine, Ratio: 4},
+	{Unit: terran.Marauder, Ratio: 1},
+}
+
 var Standard = bot.Strategy{
 	Name: "Standard",
 	Steps: bot.BuildOrder{
@@ -17,7 +26,7 @@ var Standard = bot.Strategy{
 		orbitalCommandStep(1),
 		addonStep("Barracks Reactor", terran.Barracks, terran.BarracksReactor, ability.Build_Reactor_Barracks, 1),
 		expandStep(2),
-		&marineStep,
+		armyStep(standardArmy),
 		attackWaveStep(fullSupplyWaveConfig()),
 		buildingStep("Barracks", terran.Barracks, ability.Build_Barracks, 3, terran.SupplyDepot),
 		orbitalCommandStep(2),
@@ -27,7 +36,7 @@ var Standard = bot.Strategy{
 		buildingStep("Factory", terran.Factory, ability.Build_Factory, 1, terran.BarracksTechLab),
 		buildingStep("Engineering Bay", terran.EngineeringBay, ability.Build_EngineeringBay, 1, terran.SupplyDepot),
 		upgradeStep("Infantry Weapons Level 1", ability.Research_TerranInfantryWeaponsLevel1, terran.EngineeringBay),
-		attackWaveStep(firstWaveConfig()),
+		attackWaveStep(firstWaveConfig(1)),
 		refineryStep(4),
 		buildingStep("Barracks", terran.Barracks, ability.Build_Barracks, 5, terran.SupplyDepot),
 		buildingStep("Starport", terran.Starport, ability.Build_Starport, 1, terran.Factory),
@@ -35,13 +44,12 @@ var Standard = bot.Strategy{
 		addonStep("Barracks Reactor", terran.Barracks, terran.BarracksReactor, ability.Build_Reactor_Barracks, 3),
 		// Switch Starport and Factory
 		addonStep("Starport Reactor", terran.Starport, terran.StarportReactor, ability.Build_Reactor_Starport, 1), // Factory Tech Lab
-		// Medivac (x4)
-		// Siege Tank (x2)
+		harassStep(),
 		upgradeStep("Infantry Armor Level 1", ability.Research_TerranInfantryArmorLevel1, terran.EngineeringBay),
 
 		// At this point, we should have enough units to launch a bigger attack.
 		// TODO: Update to a second wave
-		attackWaveStep(firstWaveConfig()),
+		attackWaveStep(firstWaveConfig(2)),
 
 		// Things to do over and over again
 		expandStep(0),
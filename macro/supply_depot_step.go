@@ -19,6 +19,7 @@ import (
 // we're supply blocked.
 var supplyDepotStep = bot.BuildStep{
 	Name: "Supply Depot",
+	Cost: bot.AbilityCost(ability.Build_SupplyDepot),
 	Predicate: func(b *bot.Bot) bool {
 		if !b.CanBuy(ability.Build_SupplyDepot) {
 			return false
@@ -0,0 +1,200 @@
+package macro
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/aiseeq/s2l/lib/scl"
+)
+
+// Trace records when each step of a build order became ready, executed and
+// completed so the timings can be compared against reference builds.
+type Trace struct {
+	Strategy string       `json:"strategy"`
+	Steps    []*StepTrace `json:"steps"`
+}
+
+// StepTrace is the timing of a single build step. Events that never happened
+// are nil.
+type StepTrace struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+
+	// Ready is when the step's Predicate first became true.
+	Ready *TracePoint `json:"ready,omitempty"`
+
+	// Executed is when the step's Execute first issued commands.
+	Executed *TracePoint `json:"executed,omitempty"`
+
+	// Done is when the step's Next first passed.
+	Done *TracePoint `json:"done,omitempty"`
+}
+
+// TracePoint is a moment in the game.
+type TracePoint struct {
+	Loop   int `json:"loop"`
+	Supply int `json:"supply"`
+}
+
+// NewTrace creates an empty trace for a strategy.
+func NewTrace(s *bot.Strategy) *Trace {
+	steps := make([]*StepTrace, 0, len(s.Steps))
+	for i, step := range s.Steps {
+		steps = append(steps, &StepTrace{Index: i + 1, Name: step.Name})
+	}
+
+	return &Trace{Strategy: s.Name, Steps: steps}
+}
+
+// now is the current moment in the game.
+func now(b *bot.Bot) *TracePoint {
+	return &TracePoint{Loop: b.Loop, Supply: b.FoodUsed}
+}
+
+// ready records that a step's Predicate is true.
+func (t *Trace) ready(b *bot.Bot, i int) {
+	if t != nil && i < len(t.Steps) && t.Steps[i].Ready == nil {
+		t.Steps[i].Ready = now(b)
+	}
+}
+
+// executed records that a step's Execute issued commands.
+func (t *Trace) executed(b *bot.Bot, i int) {
+	if t != nil && i < len(t.Steps) && t.Steps[i].Executed == nil {
+		t.Steps[i].Executed = now(b)
+	}
+}
+
+// done records that a step's Next passed.
+func (t *Trace) done(b *bot.Bot, i int) {
+	if t != nil && i < len(t.Steps) && t.Steps[i].Done == nil {
+		t.Steps[i].Done = now(b)
+	}
+}
+
+// Save writes the trace to a file. The format is CSV when the file ends with
+// ".csv" and JSON otherwise.
+func (t *Trace) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create trace file %q: %w", path, err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		err = t.WriteCSV(file)
+	} else {
+		err = t.WriteJSON(file)
+	}
+
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close trace file %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// WriteJSON writes the trace as indented JSON.
+func (t *Trace) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+
+	if err := encoder.Encode(t); err != nil {
+		return fmt.Errorf("failed to write trace as JSON: %w", err)
+	}
+
+	return nil
+}
+
+// WriteCSV writes the trace with one row per step. Events that never happened
+// are left empty.
+func (t *Trace) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{
+		"index", "name",
+		"ready_loop", "ready_supply",
+		"executed_loop", "executed_supply",
+		"done_loop", "done_supply",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write trace header: %w", err)
+	}
+
+	for _, step := range t.Steps {
+		record := []string{strconv.Itoa(step.Index), step.Name}
+		for _, point := range []*TracePoint{step.Ready, step.Executed, step.Done} {
+			if point == nil {
+				record = append(record, "", "")
+				continue
+			}
+
+			record = append(record, strconv.Itoa(point.Loop), strconv.Itoa(point.Supply))
+		}
+
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write trace of step %d: %w", step.Index, err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write trace as CSV: %w", err)
+	}
+
+	return nil
+}
+
+// WriteSummary writes a human-readable table of the trace. The stall column is
+// how long the step waited between becoming ready and being done.
+func (t *Trace) WriteSummary(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(table, "#\tStep\tReady\tExecuted\tDone\tStall\n")
+	for _, step := range t.Steps {
+		stall := "-"
+		if step.Ready != nil && step.Done != nil {
+			stall = GameTime(step.Done.Loop - step.Ready.Loop)
+		}
+
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			step.Index, step.Name,
+			step.Ready, step.Executed, step.Done,
+			stall,
+		)
+	}
+
+	if err := table.Flush(); err != nil {
+		return fmt.Errorf("failed to write trace summary: %w", err)
+	}
+
+	return nil
+}
+
+// String formats the moment like "1:23 @ 19", or "-" if it never happened.
+func (p *TracePoint) String() string {
+	if p == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("%s @ %d", GameTime(p.Loop), p.Supply)
+}
+
+// GameTime formats a number of game loops as minutes and seconds.
+func GameTime(loops int) string {
+	d := time.Duration(float64(loops) / scl.FPS * float64(time.Second))
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
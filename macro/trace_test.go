@@ -0,0 +1,54 @@
+package macro_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/macro"
+)
+
+func tracedStrategy() *macro.Trace {
+	trace := macro.NewTrace(&bot.Strategy{
+		Name:  "Traced",
+		Steps: bot.BuildOrder{{Name: "Supply Depot"}, {Name: "Barracks"}},
+	})
+
+	trace.Steps[0].Ready = &macro.TracePoint{Loop: 224, Supply: 14}
+	trace.Steps[0].Executed = &macro.TracePoint{Loop: 240, Supply: 14}
+	trace.Steps[0].Done = &macro.TracePoint{Loop: 1568, Supply: 15}
+	return trace
+}
+
+func TestTrace_WriteCSV(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := tracedStrategy().WriteCSV(&buffer); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	expected := "index,name,ready_loop,ready_supply,executed_loop,executed_supply,done_loop,done_supply\n" +
+		"1,Supply Depot,224,14,240,14,1568,15\n" +
+		"2,Barracks,,,,,,\n"
+	if buffer.String() != expected {
+		t.Errorf("WriteCSV() = %q, expected %q", buffer.String(), expected)
+	}
+}
+
+func TestTrace_WriteSummary(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := tracedStrategy().WriteSummary(&buffer); err != nil {
+		t.Fatalf("WriteSummary() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("len(lines) = %d, expected %d", len(lines), 3)
+	}
+
+	for _, expected := range []string{"Supply Depot", "0:10 @ 14", "1:10 @ 15", "1:00"} {
+		if !strings.Contains(lines[1], expected) {
+			t.Errorf("lines[1] = %q, expected it to contain %q", lines[1], expected)
+		}
+	}
+}
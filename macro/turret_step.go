@@ -4,6 +4,7 @@ import (
 	"github.com/NatoBoram/BlackCompany/bot"
 	"github.com/NatoBoram/BlackCompany/filter"
 	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/NatoBoram/BlackCompany/opponent"
 	"github.com/NatoBoram/BlackCompany/wheel"
 	"github.com/aiseeq/s2l/lib/scl"
 	"github.com/aiseeq/s2l/protocol/enums/ability"
@@ -11,9 +12,11 @@ import (
 )
 
 // turretStep builds missile turrets in mineral lines then around buildings when
-// flying enemies are detected
+// flying enemies are detected or the enemy is going for air units or dark
+// templars
 var turretStep = bot.BuildStep{
 	Name: "Missile Turret",
+	Cost: bot.AbilityCost(ability.Build_MissileTurret),
 	Predicate: func(b *bot.Bot) bool {
 		if b.Units.My.OfType(terran.EngineeringBay).Filter(scl.Ready).Empty() {
 			return false
@@ -63,7 +66,8 @@ var turretStep = bot.BuildStep{
 			return
 		}
 
-		if !b.State.DetectedEnemyAirArmy {
+		opening := b.State.EnemyOpening
+		if !b.State.DetectedEnemyAirArmy && !opening.Is(opponent.MassAir, 0.5) && !opening.Is(opponent.DarkTemplar, 0.5) {
 			return
 		}
 
@@ -11,6 +11,7 @@ import (
 func upgradeStep(name string, abilityId api.AbilityID, buildingId api.UnitTypeID) *bot.BuildStep {
 	return &bot.BuildStep{
 		Name: name,
+		Cost: bot.AbilityCost(abilityId),
 		Predicate: func(b *bot.Bot) bool {
 			if !b.CanBuy(abilityId) {
 				return false
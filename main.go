@@ -2,43 +2,122 @@ package main
 
 import (
 	"fmt"
-	"math"
+	"os"
+	"strings"
 
+	"github.com/NatoBoram/BlackCompany/agent"
 	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/history"
 	"github.com/NatoBoram/BlackCompany/log"
 	"github.com/NatoBoram/BlackCompany/macro"
-	"github.com/NatoBoram/BlackCompany/micro"
-	"github.com/aiseeq/s2l/lib/point"
-	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/NatoBoram/BlackCompany/record"
 	"github.com/aiseeq/s2l/protocol/api"
 	"github.com/aiseeq/s2l/protocol/client"
 )
 
 func main() {
+	flags := loadFlags()
+	if flags.StrategyFile != "" {
+		strategy, err := loadStrategyFile(flags.StrategyFile)
+		if err != nil {
+			log.Fatal("failed to load the strategy file: %v", err)
+		}
+
+		if flags.Strategy == "" {
+			flags.Strategy = strategy.Name
+		}
+	}
+
+	if flags.Strategy == "" {
+		flags.Strategy = macro.Standard.Name
+	}
+
+	if flags.ListStrategies {
+		listStrategies()
+		return
+	}
+
+	if !strings.EqualFold(flags.Strategy, macro.RandomStrategy) {
+		if _, err := macro.FindStrategy(flags.Strategy); err != nil {
+			log.Fatal("failed to find the strategy: %v", err)
+		}
+	}
+
+	if flags.Playback != "" {
+		if err := playback(flags.Playback, strategist(flags.Strategy)); err != nil {
+			log.Fatal("failed to play back %q: %v", flags.Playback, err)
+		}
+		return
+	}
+
 	env, err := loadEnv()
 	if err != nil {
 		log.Fatal("failed to load environment variables: %v", err)
 	}
 
-	cfg, err := launch(env)
+	if flags.Replays != "" {
+		if err := extractBuildOrders(env, flags); err != nil {
+			log.Fatal("failed to extract build orders: %v", err)
+		}
+		return
+	}
+
+	if flags.Games > 0 {
+		if strings.EqualFold(flags.Opponent, OpponentBot) {
+			log.Fatal("batches can only be played against the computer")
+		}
+
+		runBatch(env, flags)
+		return
+	}
+
+	opponent, err := opponentSetup(flags)
+	if err != nil {
+		log.Fatal("failed to set up the opponent: %v", err)
+	}
+
+	cfg, err := launch(env, flags, opponent)
 	if err != nil {
 		log.Fatal("failed to launch the game: %v", err)
 	}
 
-	flags := loadFlags()
-	if flags.Replay == "" {
-		runAgent(cfg.Client)
+	if flags.Replay != "" {
+		analyzeReplay(cfg.Client, flags)
+		return
 	}
+
+	game := runAgent(cfg.Client, flags)
+	saveTrace(game.Trace, flags.Trace)
+	match := history.NewMatch(game, MatchDifficulty(flags, opponent))
+	match.OpponentId = flags.OpponentId
+	saveMatch(game, match, flags.History)
 }
 
 // launch launches the game. It'll check for PROTON_PATH before launching the
-// game in Proton or fallback to s2l's default behaviour.
-func launch(env *Env) (*client.GameConfig, error) {
+// game in Proton or fallback to s2l's default behaviour. When a game port is
+// provided, it joins a game hosted by another process instead. When a replay is
+// provided, it starts the replay instead of a game.
+func launch(env *Env, flags Flags, opponent *api.PlayerSetup) (*client.GameConfig, error) {
 	bot := client.NewParticipant(api.Race_Terran, "BlackCompany")
-	cpu := client.NewComputer(api.Race_Random, api.Difficulty_Hard, api.AIBuild_RandomBuild)
+
+	if flags.LadderServer != "" {
+		c, err := JoinLadder(flags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to join the ladder game: %w", err)
+		}
+
+		return &client.GameConfig{Client: c}, nil
+	}
+
+	if flags.GamePort > 0 {
+		return joinConfig(env, flags, bot)
+	}
 
 	if env.PROTON_PATH != "" && env.STEAM_COMPAT_DATA_PATH != "" {
-		flags := loadFlags()
+		// Replays can't be started in Proton without their map
+		if flags.Replay != "" && flags.Map == "" {
+			return nil, fmt.Errorf("failed to start replay %q: no map provided, use -map with Proton", flags.Replay)
+		}
 
 		paths, err := sc2Paths(env)
 		if err != nil {
@@ -49,63 +128,89 @@ func launch(env *Env) (*client.GameConfig, error) {
 			return nil, fmt.Errorf("failed to launch StarCraft II using Proton: %w", err)
 		}
 
-		if flags.Replay != "" && flags.Map != "" {
+		if flags.Replay != "" {
 			return replayConfig(flags)
 		}
 
-		return protonConfig(bot, cpu), nil
+		config := client.NewGameConfig(bot, opponent)
+		config.Connect(flags.Port)
+		return hostGame(config, flags, gameMap(flags), opponent)
+	}
+
+	client.SetMap(gameMap(flags))
+	client.LaunchPortStart = flags.Port
+
+	if flags.Replay != "" {
+		config, err := launchObserver(env, flags)
+		if err != nil {
+			return nil, err
+		}
+
+		return startReplay(config, flags.Replay, flags)
 	}
 
-	return client.LaunchAndJoin(bot, cpu), nil
+	config := client.NewGameConfig(bot, opponent)
+	config.LaunchStarcraft()
+	return hostGame(config, flags, client.MapPath(), opponent)
 }
 
 // runAgent creates a bot and runs it.
-func runAgent(c *client.Client) {
-	b := &bot.Bot{
-		Bot: scl.New(c, bot.OnUnitCreated),
-		State: bot.BotState{
-			CcForExp:            make(map[api.UnitTag]point.Point),
-			CcForOrbitalCommand: 0,
-			AttackWaves:         bot.AttackWaves{},
-		},
-	}
-
-	b.FramesPerOrder = 16
-	b.LastLoop = -math.MaxInt
-
-	stop := make(chan struct{})
-	b.Init(stop)
-	b.Observe()
-	b.InitState()
-
-	var lastStep string
-	for b.Client.Status == api.Status_in_game {
-		b.Step()
-		lastStep = macro.Step(b, &macro.Standard, lastStep)
-		micro.Step(b)
-
-		// Once a step is done, send it to the game
-		b.Cmds.Process(&b.Actions)
-		if len(b.Actions) > 0 {
-			if _, err := b.Client.Action(api.RequestAction{Actions: b.Actions}); err != nil {
-				log.Warn("Failed to send actions: %v", err)
-			}
-
-			b.Actions = nil
-		}
+func runAgent(c *client.Client, flags Flags) *agent.Game {
+	if flags.Record == "" {
+		return agent.Run(bot.New(c), strategist(flags.Strategy), nil)
+	}
 
-		step := api.RequestStep{Count: uint32(b.FramesPerOrder)}
-		if _, err := b.Client.Step(step); err != nil {
-			if err.Error() == "Not in a game" {
-				break
-			}
+	return runRecorded(c, flags)
+}
 
-			log.Error("An unknown error occurred while stepping: %v", err)
-			break
-		}
+// runRecorded runs a bot while recording the game.
+func runRecorded(c *client.Client, flags Flags) *agent.Game {
+	recorder, err := record.Create(flags.Record)
+	if err != nil {
+		log.Error("Failed to start recording: %v", err)
+		return agent.Run(bot.New(c), strategist(flags.Strategy), nil)
+	}
+
+	log.Info("Recording the game to %q", flags.Record)
+	game := agent.Run(bot.New(c), strategist(flags.Strategy), recorder)
+
+	if err := recorder.Close(); err != nil {
+		log.Error("Failed to save the recording: %v", err)
+	}
+
+	return game
+}
+
+// saveTrace prints the build order's timings and saves them when a path is
+// provided.
+func saveTrace(trace *macro.Trace, path string) {
+	log.Info("Build order timings of %s:", trace.Strategy)
+	if err := trace.WriteSummary(os.Stdout); err != nil {
+		log.Warn("Failed to print the build order timings: %v", err)
+	}
+
+	if path == "" {
+		return
+	}
+
+	if err := trace.Save(path); err != nil {
+		log.Error("Failed to save the build order timings: %v", err)
+		return
+	}
+
+	log.Info("Saved the build order timings to %q", path)
+}
+
+// saveMatch adds the game to the match history when it ended with a result.
+func saveMatch(game *agent.Game, match history.Match, path string) {
+	if path == "" || !game.Over() {
+		return
+	}
 
-		b.Observe()
+	if err := history.Append(path, match); err != nil {
+		log.Error("Failed to save the match history: %v", err)
+		return
 	}
 
-	stop <- struct{}{}
+	log.Info("Saved the match to %q", path)
 }
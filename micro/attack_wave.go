@@ -2,14 +2,17 @@ package micro
 
 import (
 	"math/rand"
+	"slices"
 
 	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/combat"
 	"github.com/NatoBoram/BlackCompany/filter"
 	"github.com/NatoBoram/BlackCompany/log"
 	"github.com/NatoBoram/BlackCompany/sight"
 	"github.com/aiseeq/s2l/lib/point"
 	"github.com/aiseeq/s2l/lib/scl"
 	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
 )
 
 func handleAttackWaves(b *bot.Bot) {
@@ -21,7 +24,7 @@ func handleAttackWaves(b *bot.Bot) {
 			continue
 		}
 
-		handleAttackWave(b, &wave)
+		handleAttackWave(b, &wave, units)
 		keep = append(keep, wave)
 	}
 
@@ -31,20 +34,211 @@ func handleAttackWaves(b *bot.Bot) {
 // trimWave removes units that are no longer alive from the attack wave.
 func trimWave(b *bot.Bot, a *bot.AttackWave) scl.Units {
 	units := a.Units(b)
-	a.Tags = units.Tags()
+	a.Tags = withPassengers(units)
 	return units
 }
 
-func handleAttackWave(b *bot.Bot, a *bot.AttackWave) {
-	units := trimWave(b, a)
-	if units.Empty() {
-		return
+// withPassengers are the tags of units and of the units carried by their
+// medivacs, which don't appear in observations.
+func withPassengers(units scl.Units) scl.Tags {
+	tags := units.Tags()
+	for _, medivac := range units.OfType(terran.Medivac) {
+		for _, passenger := range medivac.Passengers {
+			tags = append(tags, passenger.Tag)
+		}
+	}
+
+	return tags
+}
+
+// Thresholds of the attack wave state machine.
+const (
+	// homeRadius is how close to home a wave fights to the end instead of
+	// retreating, since retreating wouldn't save anything.
+	homeRadius = 20
+
+	// regroupRadius is how close to home a retreating wave starts regrouping
+	// and how close fresh units must be to join it.
+	regroupRadius = 10
+
+	// minHealthRatio is the ratio of health under which a wave retreats.
+	minHealthRatio = 0.4
+
+	// maxLossRatio is the ratio of units that a wave can lose since it went
+	// out before it retreats.
+	maxLossRatio = 0.5
+)
+
+func handleAttackWave(b *bot.Bot, a *bot.AttackWave, units scl.Units) {
+	home := homeOf(b, units)
+	decision := advance
+	if a.State != bot.Retreating && a.State != bot.Regrouping {
+		decision = decideEngagement(b, units)
+	}
+
+	updateWaveState(b, a, units, home, decision)
+
+	// Tanks and medivacs have their own micro instead of attack-moving with the
+	// wave
+	tanks := units.OfType(terran.SiegeTank, terran.SiegeTankSieged)
+	units = units.Filter(filter.NotIn(tanks), filter.NotIn(units.OfType(terran.Medivac)))
+	handleWaveTanks(b, a, tanks, home, decision)
+
+	switch a.State {
+	case bot.Gathering:
+		recenterWave(units, a.Target)
+	case bot.Moving:
+		advanceWave(b, a, units)
+	case bot.Engaging:
+		if decision == engage {
+			advanceWave(b, a, units)
+		} else {
+			holdWave(b, a, units)
+		}
+	case bot.Retreating:
+		retreatWave(b, units, home)
+	case bot.Regrouping:
+		regroupWave(b, a, home)
 	}
 
-	units = recenterWave(units, a.Target)
-	advanceWave(a, units)
+	if a.State == bot.Moving || a.State == bot.Engaging {
+		updateWaveTarget(b, a)
+	}
+}
+
+// updateWaveState moves an attack wave to its next state. Waves retreat when
+// they would lose the fight, when they lost too many units or too much health
+// unless they're close to home, then they regroup until they're strong enough
+// to go out again.
+func updateWaveState(b *bot.Bot, a *bot.AttackWave, units scl.Units, home point.Point, decision engagement) {
+	previous := a.State
+
+	switch a.State {
+	case bot.Gathering:
+		if isGathered(units) {
+			a.Size = units.Len()
+			a.State = outState(b, a, units, home, decision)
+		}
+
+	case bot.Moving, bot.Engaging:
+		a.State = outState(b, a, units, home, decision)
+
+	case bot.Retreating:
+		if units.Center().IsCloserThan(regroupRadius, home) {
+			a.State = bot.Regrouping
+		}
+
+	case bot.Regrouping:
+		// Defending the bases can't wait for reinforcements
+		if cluster := b.FindEnemyClusterAtHome(); cluster.Exists() {
+			a.Target = cluster.Center()
+			a.State = bot.Gathering
+		} else if isRegrouped(b, a, units) {
+			a.State = bot.Gathering
+		}
+	}
+
+	if a.State != previous {
+		log.Info("Attack wave of %d units is %v instead of %v", units.Len(), a.State, previous)
+	}
+}
+
+// outState is the state of a wave that's out, depending on the enemies near
+// it.
+func outState(b *bot.Bot, a *bot.AttackWave, units scl.Units, home point.Point, decision engagement) bot.WaveState {
+	switch {
+	case shouldRetreat(b, a, units, home, decision):
+		return bot.Retreating
+	case !isGathered(units):
+		return bot.Gathering
+	case decision == advance:
+		return bot.Moving
+	default:
+		return bot.Engaging
+	}
+}
+
+// isGathered tells if most units of a wave are close to its center.
+func isGathered(units scl.Units) bool {
+	decentered := units.FurtherThan(sight.LineOfSightScannerSweep.Float64(), units.Center())
+	return float64(decentered.Len()) <= float64(units.Len())*0.2
+}
+
+// shouldRetreat tells if a wave that's out should pull back home. Waves close
+// to home or defending it fight to the end.
+func shouldRetreat(b *bot.Bot, a *bot.AttackWave, units scl.Units, home point.Point, decision engagement) bool {
+	if units.Center().IsCloserThan(homeRadius, home) || b.FindEnemyClusterAtHome().Exists() {
+		return false
+	}
+
+	if decision == retreat {
+		return true
+	}
+
+	if float64(units.Len()) < float64(a.Size)*(1-maxLossRatio) {
+		return true
+	}
 
-	updateWaveTarget(b, a)
+	return healthRatio(units) < minHealthRatio
+}
+
+// isRegrouped tells if a regrouping wave is at least as big as when it last
+// went out and would beat the known enemy army.
+func isRegrouped(b *bot.Bot, a *bot.AttackWave, units scl.Units) bool {
+	if units.Len() < a.Size {
+		return false
+	}
+
+	army := b.State.EnemyMemory.Army()
+	return army.Empty() || b.Simulate(units, army).Winner == combat.Ally
+}
+
+// healthRatio is the remaining health of units over their maximum health.
+func healthRatio(units scl.Units) float64 {
+	hits, hitsMax := 0.0, 0.0
+	for _, u := range units {
+		hits += u.Hits
+		hitsMax += u.HitsMax
+	}
+
+	if hitsMax <= 0 {
+		return 1
+	}
+
+	return hits / hitsMax
+}
+
+// homeOf is where a wave retreats, the rally point or the closest base.
+func homeOf(b *bot.Bot, units scl.Units) point.Point {
+	if rally := b.RallyPoint(); rally != nil {
+		return *rally
+	}
+
+	townHalls := b.FindTownHalls()
+	if townHalls.Exists() {
+		return townHalls.ClosestTo(units.Center()).Point()
+	}
+
+	return b.Locs.MyStart
+}
+
+// regroupWave merges the fresh units waiting at home into a regrouping wave and
+// brings back its stragglers.
+func regroupWave(b *bot.Bot, a *bot.AttackWave, home point.Point) {
+	fresh := b.FindArmy().
+		Filter(scl.Ready, filter.NotIn(b.State.AttackWaves.Units(b))).
+		CloserThan(regroupRadius, home)
+
+	if fresh.Exists() {
+		a.Tags = append(a.Tags, fresh.Tags()...)
+		log.Info("Merging %d fresh units into an attack wave", fresh.Len())
+	}
+
+	for _, u := range a.Units(b).FurtherThan(regroupRadius, home) {
+		if filter.IsNotOrderedToTarget(ability.Move, home)(u) {
+			u.CommandPos(ability.Move, home)
+		}
+	}
 }
 
 // recenterWave moves units that are too far from the wave towards the center of
@@ -67,7 +261,7 @@ func recenterWave(units scl.Units, target point.Point) scl.Units {
 	towards := center.Towards(target, 1)
 
 	log.Debug("Recentering %d units", decentered.Len())
-	for _, unit := range units {
+	for _, unit := range slices.Clone(units) {
 		if filter.IsNotOrderedToTarget(ability.Move, towards)(unit) {
 			unit.CommandPos(ability.Move, towards)
 		}
@@ -78,13 +272,19 @@ func recenterWave(units scl.Units, target point.Point) scl.Units {
 	return units
 }
 
-// advanceWave moves the attack wave towards the target.
-func advanceWave(a *bot.AttackWave, units scl.Units) scl.Units {
+// advanceWave moves the attack wave towards the target. Units that already
+// have enemies in range are left to their own micro.
+func advanceWave(b *bot.Bot, a *bot.AttackWave, units scl.Units) scl.Units {
 	if units.Empty() {
 		return units
 	}
 
-	for _, u := range units {
+	for _, u := range slices.Clone(units) {
+		if hasTargetInRange(b, u) {
+			units.Remove(u)
+			continue
+		}
+
 		if filter.IsNotOrderedToTarget(ability.Attack, a.Target)(u) {
 			u.CommandPos(ability.Attack, a.Target)
 		}
@@ -95,6 +295,11 @@ func advanceWave(a *bot.AttackWave, units scl.Units) scl.Units {
 	return units
 }
 
+// hasTargetInRange tells if a unit can shoot a visible enemy without moving.
+func hasTargetInRange(b *bot.Bot, u *scl.Unit) bool {
+	return visibleEnemies(b).InRangeOf(u, 0).Exists()
+}
+
 // updateWaveTarget changes the focus of this attack wave to something else.
 func updateWaveTarget(b *bot.Bot, a *bot.AttackWave) {
 	center := a.Units(b).Center()
@@ -145,6 +350,19 @@ func updateWaveTarget(b *bot.Bot, a *bot.AttackWave) {
 		return
 	}
 
+	// Buildings that went back in the fog of war are probably still there
+	remembered := b.State.EnemyMemory.Structures()
+	if remembered.Exists() {
+		target := remembered.ClosestTo(center).Point()
+
+		if target.Dist(a.Target) > sight.LineOfSightScannerSweep.Float64() {
+			log.Info("Switching target to a remembered building at %v", target)
+		}
+
+		a.Target = target
+		return
+	}
+
 	// Expansions are obvious choices for building locations
 	target := b.Locs.EnemyExps[rand.Intn(len(b.Locs.EnemyExps))]
 
@@ -0,0 +1,112 @@
+package micro
+
+import (
+	"slices"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/combat"
+	"github.com/NatoBoram/BlackCompany/filter"
+	"github.com/NatoBoram/BlackCompany/sight"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+)
+
+// engagement is what an attack wave does against the enemies near it.
+type engagement int
+
+const (
+	// advance keeps moving towards the target since no enemy is near.
+	advance engagement = iota
+
+	// engage fights the enemies near the wave.
+	engage
+
+	// hold stops advancing but fights the enemies that come in range.
+	hold
+
+	// retreat goes back home.
+	retreat
+)
+
+// decideEngagement simulates the fight between an attack wave and the enemies
+// near it. The wave engages fights it wins, holds its position when the trade
+// is still favourable and retreats otherwise. It never retreats while the
+// enemy is in our bases.
+func decideEngagement(b *bot.Bot, units scl.Units) engagement {
+	if units.Empty() {
+		return advance
+	}
+
+	enemies := b.FindEnemyArmyNear(units.Center(), sight.LineOfSightScannerSweep.Float64())
+	if enemies.Empty() {
+		return advance
+	}
+
+	outcome := b.Simulate(units, enemies)
+	switch {
+	case outcome.Winner == combat.Ally:
+		return engage
+	case outcome.EnemyLostValue >= outcome.AllyLostValue:
+		return hold
+	case b.FindEnemyClusterAtHome().Exists():
+		return hold
+	default:
+		return retreat
+	}
+}
+
+// holdWave stops the units of an attack wave that are moving so they only
+// fight the enemies that come in range.
+func holdWave(b *bot.Bot, a *bot.AttackWave, units scl.Units) scl.Units {
+	for _, u := range slices.Clone(units) {
+		if hasTargetInRange(b, u) {
+			units.Remove(u)
+			continue
+		}
+
+		if filter.IsOrderedToTarget(ability.Attack, a.Target)(u) || filter.IsOrderedTo(ability.Move)(u) {
+			u.Command(ability.HoldPosition)
+		}
+
+		units.Remove(u)
+	}
+
+	return units
+}
+
+// retreatLookahead is how far along the safest path home retreating units are
+// sent at once, so they go around threats without stopping at every cell.
+const retreatLookahead = 8
+
+// retreatWave moves the units of an attack wave back home, along the path that
+// goes through the least threat.
+func retreatWave(b *bot.Bot, units scl.Units, home point.Point) scl.Units {
+	if units.Empty() {
+		return units
+	}
+
+	waypoint := retreatWaypoint(b, units.ClosestTo(units.Center()), home)
+
+	for _, u := range slices.Clone(units) {
+		if filter.IsNotOrderedToTarget(ability.Move, waypoint)(u) {
+			u.CommandPos(ability.Move, waypoint)
+		}
+
+		units.Remove(u)
+	}
+
+	return units
+}
+
+// retreatWaypoint is where a retreating unit goes next on the safest path home.
+// It's home itself once the unit is close enough to it.
+func retreatWaypoint(b *bot.Bot, unit *scl.Unit, home point.Point) point.Point {
+	for _, waypoint := range b.SafestPath(unit, home) {
+		if waypoint.IsFurtherThan(retreatLookahead, unit) {
+			return waypoint
+		}
+	}
+
+	return home
+}
@@ -0,0 +1,260 @@
+package micro
+
+import (
+	"slices"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/filter"
+	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+)
+
+const (
+	// waypointRadius is how close a squad gets to a waypoint before heading to
+	// the next one.
+	waypointRadius = 3
+
+	// harassRadius is how far around a squad enemy workers are counted and
+	// defences are looked for.
+	harassRadius = 10
+)
+
+func handleHarassSquads(b *bot.Bot) {
+	dead := b.Obs.RawData.Event.GetDeadUnits()
+	keep := make(bot.HarassSquads, 0, len(b.State.HarassSquads))
+
+	for _, squad := range b.State.HarassSquads {
+		countLosses(&squad, dead)
+
+		units := trimSquad(b, &squad)
+		if units.Empty() || handleHarassSquad(b, &squad, units) {
+			finishSquad(b, &squad)
+			continue
+		}
+
+		keep = append(keep, squad)
+	}
+
+	b.State.HarassSquads = keep
+}
+
+// countLosses counts the enemy workers killed and the units lost by a squad.
+// Only the workers that were near its target while it was harassing count.
+func countLosses(s *bot.HarassSquad, dead []api.UnitTag) {
+	for _, tag := range dead {
+		if slices.Contains(s.Tags, tag) {
+			s.UnitsLost++
+		}
+
+		if slices.Contains(s.Workers, tag) {
+			s.WorkersKilled++
+		}
+	}
+}
+
+// trimSquad removes units that are no longer alive from a harass squad.
+func trimSquad(b *bot.Bot, s *bot.HarassSquad) scl.Units {
+	units := s.Units(b)
+	s.Tags = withPassengers(units)
+	return units
+}
+
+// finishSquad adds the results of a harass squad to the bot's.
+func finishSquad(b *bot.Bot, s *bot.HarassSquad) {
+	b.State.Harassment.Squads++
+	b.State.Harassment.WorkersKilled += s.WorkersKilled
+	b.State.Harassment.UnitsLost += s.UnitsLost
+
+	log.Info("Harass squad killed %d workers and lost %d units", s.WorkersKilled, s.UnitsLost)
+}
+
+// handleHarassSquad moves a harass squad through its lifecycle and tells if
+// it's back home.
+func handleHarassSquad(b *bot.Bot, s *bot.HarassSquad, units scl.Units) bool {
+	medivac := units.OfType(terran.Medivac).First()
+	ground := units.Filter(scl.Ground)
+
+	updateSquadState(b, s, units, medivac, ground)
+	trackWorkers(b, s)
+
+	switch s.State {
+	case bot.Loading:
+		loadSquad(medivac, ground)
+	case bot.Travelling, bot.Leaving:
+		travel(b, s, units, medivac, ground)
+	case bot.Harassing:
+		harass(b, s, medivac, ground)
+	}
+
+	if s.State != bot.Leaving || s.Waypoint < len(s.Path) {
+		return false
+	}
+
+	// Passengers are dropped at home before the squad breaks up
+	if medivac != nil && medivac.CargoSpaceTaken > 0 {
+		if !filter.IsOrderedTo(ability.UnloadAllAt_Medivac)(medivac) {
+			medivac.CommandTag(ability.UnloadAllAt_Medivac, medivac.Tag)
+		}
+		return false
+	}
+
+	return true
+}
+
+// trackWorkers remembers the enemy workers near the target of a harassing
+// squad so the ones that die there are counted as killed. Workers that die
+// while the squad travels or leaves weren't killed by it.
+func trackWorkers(b *bot.Bot, s *bot.HarassSquad) {
+	if s.State != bot.Harassing {
+		s.Workers = nil
+		return
+	}
+
+	s.Workers = enemyWorkers(b).CloserThan(harassRadius, s.Target).Tags()
+}
+
+// updateSquadState moves a harass squad to its next state. Squads leave as
+// soon as they see a defence or when no worker is left to kill.
+func updateSquadState(b *bot.Bot, s *bot.HarassSquad, units scl.Units, medivac *scl.Unit, ground scl.Units) {
+	previous := s.State
+	center := units.Center()
+
+	switch s.State {
+	case bot.Loading:
+		if medivac == nil || ground.Empty() {
+			s.Path = b.EdgePath(center, s.Target)
+			s.Waypoint = 0
+			s.State = bot.Travelling
+		}
+
+	case bot.Travelling:
+		if isDefended(b, center) {
+			leave(b, s, units)
+		} else if s.Waypoint >= len(s.Path) {
+			s.State = bot.Harassing
+		}
+
+	case bot.Harassing:
+		if isDefended(b, center) || enemyWorkers(b).CloserThan(harassRadius, s.Target).Empty() {
+			leave(b, s, units)
+		}
+	}
+
+	if s.State != previous {
+		log.Info("Harass squad of %d units is %v instead of %v", s.Tags.Len(), s.State, previous)
+	}
+}
+
+// leave sends a squad back home along the edges of the map.
+func leave(b *bot.Bot, s *bot.HarassSquad, units scl.Units) {
+	s.Path = b.EdgePath(units.Center(), homeOf(b, units))
+	s.Waypoint = 0
+	s.State = bot.Leaving
+}
+
+// isDefended tells if enemy units or static defences that can fight are near a
+// position.
+func isDefended(b *bot.Bot, pos point.Point) bool {
+	if b.FindEnemyArmyNear(pos, harassRadius).Exists() {
+		return true
+	}
+
+	return visibleEnemies(b).Filter(scl.Structure).CloserThan(harassRadius, pos).Filter(func(u *scl.Unit) bool {
+		return u.GroundDPS() > 0 || u.AirDPS() > 0
+	}).Exists()
+}
+
+// loadSquad makes the ground units of a squad board its medivac.
+func loadSquad(medivac *scl.Unit, ground scl.Units) {
+	if medivac == nil || ground.Empty() {
+		return
+	}
+
+	for _, u := range ground {
+		if !filter.IsOrderedToTag(ability.Smart, medivac.Tag)(u) {
+			u.CommandTag(ability.Smart, medivac.Tag)
+		}
+	}
+
+	center := ground.Center()
+	if medivac.IsFurtherThan(waypointRadius, center) && filter.IsNotOrderedToTarget(ability.Move, center)(medivac) {
+		medivac.CommandPos(ability.Move, center)
+	}
+}
+
+// travel moves a squad along its path. Drops wait for their units to board
+// before leaving.
+func travel(b *bot.Bot, s *bot.HarassSquad, units scl.Units, medivac *scl.Unit, ground scl.Units) {
+	movers := units
+	if medivac != nil {
+		if ground.Exists() {
+			loadSquad(medivac, ground)
+			return
+		}
+
+		if isTargeted(medivac, visibleEnemies(b)) && boost(medivac) {
+			return
+		}
+
+		movers = scl.Units{medivac}
+	}
+
+	for s.Waypoint < len(s.Path) && movers.Center().IsCloserThan(waypointRadius, s.Path[s.Waypoint]) {
+		s.Waypoint++
+	}
+
+	if s.Waypoint >= len(s.Path) {
+		return
+	}
+
+	waypoint := s.Path[s.Waypoint]
+	for _, u := range movers {
+		if filter.IsNotOrderedToTarget(ability.Move, waypoint)(u) {
+			u.CommandPos(ability.Move, waypoint)
+		}
+	}
+}
+
+// harass drops the units of a squad in the mineral line and makes them kill
+// the workers, starting with the ones in range that have the least health.
+func harass(b *bot.Bot, s *bot.HarassSquad, medivac *scl.Unit, ground scl.Units) {
+	if medivac != nil {
+		if medivac.CargoSpaceTaken > 0 {
+			if !filter.IsOrderedTo(ability.UnloadAllAt_Medivac)(medivac) {
+				medivac.CommandTag(ability.UnloadAllAt_Medivac, medivac.Tag)
+			}
+		} else if !healMostDamaged(medivac, ground) {
+			follow(medivac, ground, s.Target)
+		}
+	}
+
+	workers := enemyWorkers(b)
+	for _, u := range ground {
+		target := workers.InRangeOf(u, 0).Min(func(worker *scl.Unit) float64 {
+			return worker.Hits
+		})
+		if target == nil {
+			target = workers.CloserThan(harassRadius, u).ClosestTo(u)
+		}
+
+		if target == nil {
+			if filter.IsNotOrderedToTarget(ability.Attack, s.Target)(u) {
+				u.CommandPos(ability.Attack, s.Target)
+			}
+			continue
+		}
+
+		if !filter.IsOrderedToTag(ability.Attack, target.Tag)(u) {
+			u.CommandTag(ability.Attack, target.Tag)
+		}
+	}
+}
+
+// enemyWorkers are the visible enemy workers.
+func enemyWorkers(b *bot.Bot) scl.Units {
+	return visibleEnemies(b).Filter(func(u *scl.Unit) bool { return u.IsWorker() })
+}
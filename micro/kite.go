@@ -0,0 +1,79 @@
+package micro
+
+import (
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/buff"
+)
+
+const (
+	// loopsPerSecond is how many game loops are in a second of game time at
+	// normal speed, which is the unit of movement speeds.
+	loopsPerSecond = 16
+
+	// kiteMargin is added to the range of enemies so units step back before
+	// they're hit.
+	kiteMargin = 1
+
+	// minKiteDistance is the shortest step back that's worth losing the time
+	// to turn around.
+	minKiteDistance = 0.5
+
+	// stimSpeed is the speed bonus of stimmed units.
+	stimSpeed = 1.5
+)
+
+// kite steps a ranged unit back from the enemies with a shorter range while
+// its weapon is cooling down. It moves as far as it can before the weapon is
+// ready, so it attacks again as soon as it can. It tells if the unit was
+// ordered to step back, otherwise it's free to attack.
+func kite(b *bot.Bot, u *scl.Unit, enemies scl.Units) bool {
+	if u.WeaponCooldown <= 0 {
+		return false
+	}
+
+	threats := enemies.Filter(func(enemy *scl.Unit) bool {
+		enemyRange := rangeAgainst(enemy, u)
+		return enemyRange >= 0 && enemyRange < rangeAgainst(u, enemy) && enemy.RangeDelta(u, kiteMargin) <= 0
+	})
+	if threats.Empty() {
+		return false
+	}
+
+	speed := u.Speed()
+	if u.HasBuff(buff.Stimpack) || u.HasBuff(buff.StimpackMarauder) {
+		speed *= stimSpeed
+	}
+
+	distance := float64(u.WeaponCooldown) / loopsPerSecond * speed
+	if distance < minKiteDistance {
+		return false
+	}
+
+	away := u.Point().Towards(threats.Center(), -distance)
+	if !u.IsFlying && b.Grid != nil && !b.Grid.IsPathable(away) {
+		return false
+	}
+
+	u.CommandPos(ability.Move, away)
+	return true
+}
+
+// rangeAgainst is the range of the weapon an attacker uses against a target,
+// or -1 when it can't attack it.
+func rangeAgainst(attacker *scl.Unit, target *scl.Unit) float64 {
+	if target.IsFlying {
+		if attacker.AirDPS() > 0 {
+			return attacker.AirRange()
+		}
+
+		return -1
+	}
+
+	if attacker.GroundDPS() > 0 {
+		return attacker.GroundRange()
+	}
+
+	return -1
+}
@@ -1,19 +1,77 @@
 package micro
 
 import (
+	"cmp"
+	"slices"
+
 	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/filter"
+	"github.com/aiseeq/s2l/lib/point"
 	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/buff"
+	"github.com/aiseeq/s2l/protocol/enums/effect"
+	"github.com/aiseeq/s2l/protocol/enums/protoss"
 	"github.com/aiseeq/s2l/protocol/enums/terran"
 	"github.com/aiseeq/s2l/protocol/enums/zerg"
 )
 
+const (
+	// stimHealth is the ratio of health a marine needs to stim on its own.
+	// Combat shield raises the maximum health, so stim costs a smaller part.
+	stimHealth = 0.8
+
+	// supportedStimHealth is the ratio of health a marine needs to stim when
+	// a medivac can heal it.
+	supportedStimHealth = 0.5
+
+	// supportRange is how close a medivac must be to heal a marine.
+	supportRange = 6
+
+	// stimRange is added to the range of marines so they stim right before
+	// the fight starts.
+	stimRange = 2
+
+	// splashRadius is how far marines spread from each other against splash
+	// damage.
+	splashRadius = 2
+
+	// splashRange is how close splash damage must be before marines split.
+	splashRange = 6
+)
+
+// priorityTargets deal so much damage that they're shot first when they're in
+// range.
+var priorityTargets = []api.UnitTypeID{
+	zerg.Baneling,
+	protoss.HighTemplar,
+	terran.SiegeTank,
+	terran.SiegeTankSieged,
+}
+
+// splashers deal area damage that marines split against.
+var splashers = []api.UnitTypeID{
+	zerg.Baneling,
+	terran.WidowMine,
+	terran.WidowMineBurrowed,
+}
+
 func handleMarines(b *bot.Bot) {
 	marines := b.Units.My.OfType(terran.Marine)
 	if marines.Empty() {
 		return
 	}
 
+	// Retreating marines shouldn't turn around to fight, and harassing marines
+	// go for workers
+	retreating := b.State.AttackWaves.UnitsIn(b, bot.Retreating, bot.Regrouping)
+	marines = marines.Filter(filter.NotIn(retreating), filter.NotIn(b.State.HarassSquads.Units(b)))
+
 	killChangelingsOnSight(b, marines)
+	splitMarines(b, marines)
+	stimMarines(b, marines)
+	focusFire(b, marines)
 }
 
 func killChangelingsOnSight(b *bot.Bot, army scl.Units) {
@@ -31,3 +89,144 @@ func killChangelingsOnSight(b *bot.Bot, army scl.Units) {
 		unit.Attack(inSight)
 	}
 }
+
+// splitMarines spreads the marines that are clumped near banelings, widow mines
+// or storms so they don't all get hit by the same splash. Marines that can shoot
+// an enemy in range split between their shots instead of skipping them.
+func splitMarines(b *bot.Bot, marines scl.Units) {
+	splashers := b.Units.Enemy.OfType(splashers...)
+	storms := enemyStorms(b)
+	if splashers.Empty() && len(storms) == 0 {
+		return
+	}
+
+	enemies := visibleEnemies(b)
+
+	for _, marine := range marines {
+		// Getting out of storms comes first
+		if storm, ok := closestStorm(storms, marine); ok {
+			marine.CommandPos(ability.Move, marine.Point().Towards(storm, -splashRange))
+			continue
+		}
+
+		threat := splashers.CloserThan(splashRange, marine).ClosestTo(marine)
+		if threat == nil {
+			continue
+		}
+
+		if marine.WeaponCooldown <= 0 && enemies.InRangeOf(marine, 0).Exists() {
+			continue
+		}
+
+		neighbours := marines.CloserThan(splashRadius, marine).Filter(filter.IsNotTag(marine.Tag))
+		if neighbours.Empty() {
+			continue
+		}
+
+		away := neighbours.Center()
+		if away == marine.Point() {
+			away = threat.Point()
+		}
+
+		marine.CommandPos(ability.Move, marine.Point().Towards(away, -splashRadius))
+	}
+}
+
+// enemyStorms are the positions of the psionic storms cast by the enemy.
+func enemyStorms(b *bot.Bot) point.Points {
+	var storms point.Points
+	for _, e := range b.Obs.RawData.Effects {
+		if e.EffectId != effect.PsiStorm || e.Alliance == api.Alliance_Self {
+			continue
+		}
+
+		for _, pos := range e.Pos {
+			storms = append(storms, point.Pt2(pos))
+		}
+	}
+
+	return storms
+}
+
+// closestStorm finds the storm a marine is standing in.
+func closestStorm(storms point.Points, marine *scl.Unit) (point.Point, bool) {
+	for _, storm := range storms {
+		if marine.IsCloserThan(splashRadius+float64(marine.Radius), storm) {
+			return storm, true
+		}
+	}
+
+	return 0, false
+}
+
+// stimMarines stims the marines that are about to fight when they have enough
+// health, which is less when a medivac can heal them.
+func stimMarines(b *bot.Bot, marines scl.Units) {
+	if !b.Upgrades[ability.Research_Stimpack] {
+		return
+	}
+
+	enemies := b.Units.Enemy.All().Filter(scl.DpsGt5)
+	if enemies.Empty() {
+		return
+	}
+
+	medivacs := b.Units.My.OfType(terran.Medivac).Filter(func(u *scl.Unit) bool {
+		return u.Energy > 0
+	})
+
+	for _, marine := range marines {
+		if marine.HasBuff(buff.Stimpack) || marine.HealthMax <= 0 {
+			continue
+		}
+
+		if enemies.InRangeOf(marine, stimRange).Empty() {
+			continue
+		}
+
+		threshold := stimHealth
+		if medivacs.CloserThan(supportRange, marine).Exists() {
+			threshold = supportedStimHealth
+		}
+
+		if float64(marine.Health/marine.HealthMax) >= threshold {
+			marine.Command(ability.Effect_Stim_Marine)
+		}
+	}
+}
+
+// focusFire makes marines shoot the enemy in range that dies the fastest,
+// after the high value targets. Marines kite between their shots.
+func focusFire(b *bot.Bot, marines scl.Units) {
+	enemies := visibleEnemies(b)
+	if enemies.Empty() {
+		return
+	}
+
+	for _, marine := range marines {
+		inRange := enemies.InRangeOf(marine, 0)
+		if inRange.Empty() || kite(b, marine, enemies) {
+			continue
+		}
+
+		if priority := inRange.OfType(priorityTargets...); priority.Exists() {
+			inRange = priority
+		}
+
+		target := slices.MinFunc(inRange, func(a, b *scl.Unit) int {
+			return cmp.Or(cmp.Compare(a.Hits, b.Hits), cmp.Compare(a.Tag, b.Tag))
+		})
+
+		if !filter.IsOrderedToTag(ability.Attack, target.Tag)(marine) {
+			marine.CommandTag(ability.Attack, target.Tag)
+		}
+	}
+}
+
+// visibleEnemies are the enemies that can be shot, without the snapshots of
+// structures in the fog of war.
+func visibleEnemies(b *bot.Bot) scl.Units {
+	return b.Units.Enemy.All().Filter(func(u *scl.Unit) bool {
+		return u.DisplayType != api.DisplayType_Snapshot
+	})
+}
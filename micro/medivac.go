@@ -0,0 +1,206 @@
+package micro
+
+import (
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/filter"
+	"github.com/NatoBoram/BlackCompany/influence"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/buff"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+)
+
+const (
+	// followDistance is how far behind the center of the bio medivacs stay,
+	// away from the target of their wave.
+	followDistance = 3
+
+	// followSlack is how far from their position medivacs can drift before
+	// they move back.
+	followSlack = 2
+
+	// healRange is how far medivacs look for units to heal.
+	healRange = 6
+
+	// pickupHealth is the ratio of health under which threatened units are
+	// picked up by a medivac.
+	pickupHealth = 0.3
+
+	// pickupRange is how close a unit must be to be picked up.
+	pickupRange = 5
+)
+
+// bio are the units that medivacs heal and carry.
+var bio = []api.UnitTypeID{
+	terran.Marine,
+	terran.Marauder,
+	terran.Reaper,
+}
+
+// handleMedivacs makes the medivacs of attack waves support their bio. Medivacs
+// that aren't in a wave or a harass squad heal the units around them.
+func handleMedivacs(b *bot.Bot) {
+	medivacs := b.Units.My.OfType(terran.Medivac)
+	if medivacs.Empty() {
+		return
+	}
+
+	enemies := visibleEnemies(b)
+	for _, a := range b.State.AttackWaves {
+		units := a.Units(b)
+		inWave := units.OfType(terran.Medivac)
+		if inWave.Empty() {
+			continue
+		}
+
+		home := homeOf(b, units)
+		for _, medivac := range inWave {
+			supportWave(b, &a, medivac, units.OfType(bio...), enemies, home)
+		}
+	}
+
+	inWaves := b.State.AttackWaves.Units(b)
+	inSquads := b.State.HarassSquads.Units(b)
+	for _, medivac := range medivacs.Filter(filter.NotIn(inWaves), filter.NotIn(inSquads)) {
+		healMostDamaged(medivac, b.Units.My.OfType(bio...))
+	}
+}
+
+// supportWave controls a medivac of an attack wave. Medivacs rescue units about
+// to die, boost away when they're in danger and otherwise heal the most damaged
+// units while staying behind the front line.
+func supportWave(b *bot.Bot, a *bot.AttackWave, medivac *scl.Unit, bio scl.Units, enemies scl.Units, home point.Point) {
+	loaded := medivac.CargoSpaceTaken > 0
+	inDanger := !b.Influence.IsSafe(influence.Air, medivac.Point())
+	canUnload := b.Influence.IsSafe(influence.Ground, medivac.Point())
+
+	if a.State == bot.Retreating || isTargeted(medivac, enemies) || loaded && inDanger {
+		if boost(medivac) {
+			return
+		}
+	}
+
+	if loaded && canUnload {
+		if !filter.IsOrderedTo(ability.UnloadAllAt_Medivac)(medivac) {
+			medivac.CommandTag(ability.UnloadAllAt_Medivac, medivac.Tag)
+		}
+		return
+	}
+
+	// Units about to die are saved even when retreating
+	if !loaded && pickUp(b, medivac, bio) {
+		return
+	}
+
+	if loaded || a.State == bot.Retreating {
+		if filter.IsNotOrderedToTarget(ability.Move, home)(medivac) {
+			medivac.CommandPos(ability.Move, home)
+		}
+		return
+	}
+
+	if healMostDamaged(medivac, bio) {
+		return
+	}
+
+	follow(medivac, bio, a.Target)
+}
+
+// isTargeted tells if enemies are attacking a medivac or can attack it.
+func isTargeted(medivac *scl.Unit, enemies scl.Units) bool {
+	for _, enemy := range enemies {
+		if enemy.EngagedTargetTag == medivac.Tag {
+			return true
+		}
+	}
+
+	return medivac.InRangeOf(enemies, 0).Exists()
+}
+
+// boost ignites the afterburners of a medivac when they're ready.
+func boost(medivac *scl.Unit) bool {
+	if medivac.HasBuff(buff.MedivacSpeedBoost) || !medivac.HasAbility(ability.Effect_MedivacIgniteAfterburners) {
+		return false
+	}
+
+	medivac.Command(ability.Effect_MedivacIgniteAfterburners)
+	return true
+}
+
+// pickUp loads the most damaged unit that's in danger and close enough to a
+// medivac so it survives the fight.
+func pickUp(b *bot.Bot, medivac *scl.Unit, bio scl.Units) bool {
+	free := medivac.CargoSpaceMax - medivac.CargoSpaceTaken
+	if free <= 0 {
+		return false
+	}
+
+	endangered := bio.CloserThan(pickupRange, medivac).Filter(func(u *scl.Unit) bool {
+		return u.Hits < u.HitsMax*pickupHealth &&
+			int32(b.U.Types[u.UnitType].CargoSize) <= free &&
+			!b.Influence.IsSafe(influence.Ground, u.Point())
+	})
+	target := mostDamaged(endangered)
+	if target == nil {
+		return false
+	}
+
+	if !filter.IsOrderedToTag(ability.Load_Medivac, target.Tag)(medivac) {
+		medivac.CommandTag(ability.Load_Medivac, target.Tag)
+	}
+
+	return true
+}
+
+// healMostDamaged heals the unit with the lowest ratio of health near a
+// medivac.
+func healMostDamaged(medivac *scl.Unit, bio scl.Units) bool {
+	if medivac.Energy <= 0 {
+		return false
+	}
+
+	target := mostDamaged(bio.CloserThan(healRange, medivac))
+	if target == nil {
+		return false
+	}
+
+	if !filter.IsOrderedToTag(ability.Effect_Heal, target.Tag)(medivac) {
+		medivac.CommandTag(ability.Effect_Heal, target.Tag)
+	}
+
+	return true
+}
+
+// mostDamaged is the hurt unit with the lowest ratio of health.
+func mostDamaged(units scl.Units) *scl.Unit {
+	var target *scl.Unit
+	for _, u := range units {
+		if u.Hits >= u.HitsMax {
+			continue
+		}
+
+		if target == nil || u.Hits/u.HitsMax < target.Hits/target.HitsMax {
+			target = u
+		}
+	}
+
+	return target
+}
+
+// follow keeps a medivac behind the center of the bio, away from a target.
+func follow(medivac *scl.Unit, bio scl.Units, target point.Point) {
+	if bio.Empty() {
+		return
+	}
+
+	pos := bio.Center().Towards(target, -followDistance)
+	if medivac.IsCloserThan(followSlack, pos) {
+		return
+	}
+
+	if filter.IsNotOrderedToTarget(ability.Move, pos)(medivac) {
+		medivac.CommandPos(ability.Move, pos)
+	}
+}
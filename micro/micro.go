@@ -11,7 +11,12 @@ func Step(b *bot.Bot) {
 	}
 
 	handleAttackWaves(b)
+	handleHarassSquads(b)
 	handleTownHalls(b)
+	handleOrbitalCommands(b)
+	handleScout(b)
 	handleWorkers(b)
 	handleMarines(b)
+	handleMedivacs(b)
+	handleTanks(b)
 }
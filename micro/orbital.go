@@ -0,0 +1,225 @@
+package micro
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/filter"
+	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/NatoBoram/BlackCompany/sight"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/buff"
+	"github.com/aiseeq/s2l/protocol/enums/effect"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+)
+
+const (
+	// calldownEnergy is how much energy every ability of orbital commands
+	// costs.
+	calldownEnergy = 50
+
+	// cloakedRange is how close to our units cloaked enemies must be to be
+	// scanned.
+	cloakedRange = 10
+
+	// highGround is how much higher than a wave its target must be for the
+	// wave to need vision on it.
+	highGround = 1
+)
+
+// handleOrbitalCommands spends the energy of orbital commands. Scans reveal
+// cloaked enemies and high ground, extra supply lifts supply blocks and MULEs
+// use the rest, but the orbital command with the most energy keeps enough for a
+// scan when one might soon be needed.
+func handleOrbitalCommands(b *bot.Bot) {
+	ready := b.Units.My.OfType(terran.OrbitalCommand).Filter(scl.Ready, func(u *scl.Unit) bool {
+		return u.Energy >= calldownEnergy
+	})
+	slices.SortFunc(ready, func(x, y *scl.Unit) int {
+		return cmp.Compare(y.Energy, x.Energy)
+	})
+
+	if pos, reason := scanTarget(b); reason != "" && ready.Exists() {
+		log.Info("Scanning %s at %v with %.0f energy", reason, pos, ready[0].Energy)
+		ready[0].CommandPos(ability.Effect_Scan, pos)
+		ready = ready[1:]
+
+		if reason == scoutingScan {
+			scannedEnemy(b)
+		}
+	}
+
+	reserve := needsScanReserve(b)
+	if reserve != b.State.ScanReserved {
+		if reserve {
+			log.Info("Keeping %d energy for a scan", calldownEnergy)
+		} else {
+			log.Info("No longer keeping energy for a scan")
+		}
+		b.State.ScanReserved = reserve
+	}
+
+	if reserve && ready.Exists() && ready[0].Energy < 2*calldownEnergy {
+		ready = ready[1:]
+	}
+
+	if isSupplyBlocked(b) && ready.Exists() {
+		if depot := supplyDropTarget(b); depot != nil {
+			log.Info("Calling down extra supply on the supply depot at %v with %.0f energy", depot.Point(), ready[0].Energy)
+			ready[0].CommandTag(ability.Effect_SupplyDrop, depot.Tag)
+			ready = ready[1:]
+		}
+	}
+
+	for _, orbital := range ready {
+		mineral := richestMineralField(b)
+		if mineral == nil {
+			return
+		}
+
+		log.Info("Calling down a MULE on the mineral field at %v with %d minerals left", mineral.Point(), mineral.MineralContents)
+		orbital.CommandTag(ability.Effect_CalldownMULE, mineral.Tag)
+	}
+}
+
+// scanTarget finds where a scan is needed and why. Cloaked enemies near our
+// units come first, then the high ground that attack waves are about to reach,
+// then the enemy's bases when a scouting trip is due.
+func scanTarget(b *bot.Bot) (point.Point, string) {
+	scans := myScans(b)
+	scanned := func(pos point.Point) bool {
+		return slices.ContainsFunc(scans, func(scan point.Point) bool {
+			return scan.IsCloserThan(sight.LineOfSightScannerSweep.Float64(), pos)
+		})
+	}
+
+	units := b.Units.My.All().Filter(func(u *scl.Unit) bool {
+		return u.IsArmed() && !u.IsWorker()
+	})
+	for _, enemy := range cloakedEnemies(b.Units.Enemy.All()) {
+		if units.CloserThan(cloakedRange, enemy).Exists() && !scanned(enemy.Point()) {
+			return enemy.Point(), "cloaked units"
+		}
+	}
+
+	for _, a := range b.State.AttackWaves {
+		if a.State != bot.Moving && a.State != bot.Engaging {
+			continue
+		}
+
+		units := a.Units(b)
+		if units.Empty() {
+			continue
+		}
+
+		front := units.ClosestTo(a.Target)
+		if front.IsCloserThan(sight.LineOfSightScannerSweep.Float64(), a.Target) &&
+			isHighGround(b, front.Point(), a.Target) &&
+			!b.Grid.IsVisible(a.Target) &&
+			!scanned(a.Target) {
+			return a.Target, "high ground"
+		}
+	}
+
+	if target, ok := scoutScanTarget(b); ok {
+		return target, scoutingScan
+	}
+
+	return 0, ""
+}
+
+// needsScanReserve tells if a scan might soon be needed, because cloaked
+// enemies were seen, because an attack wave is headed to high ground or because
+// a scouting trip is due.
+func needsScanReserve(b *bot.Bot) bool {
+	if cloakedEnemies(b.State.EnemyMemory.Units()).Exists() {
+		return true
+	}
+
+	if _, ok := scoutScanTarget(b); ok {
+		return true
+	}
+
+	for _, a := range b.State.AttackWaves {
+		if a.State == bot.Retreating || a.State == bot.Regrouping {
+			continue
+		}
+
+		units := a.Units(b)
+		if units.Exists() && isHighGround(b, units.Center(), a.Target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cloakedEnemies are the enemies that can't be attacked until they're
+// detected.
+func cloakedEnemies(enemies scl.Units) scl.Units {
+	return enemies.Filter(func(u *scl.Unit) bool {
+		return u.Cloak == api.CloakState_Cloaked
+	})
+}
+
+// myScans are the positions of our scanner sweeps.
+func myScans(b *bot.Bot) point.Points {
+	var scans point.Points
+	for _, e := range b.Obs.RawData.Effects {
+		if e.EffectId != effect.ScannerSweep || e.Alliance != api.Alliance_Self {
+			continue
+		}
+
+		for _, pos := range e.Pos {
+			scans = append(scans, point.Pt2(pos))
+		}
+	}
+
+	return scans
+}
+
+// isHighGround tells if a target is on higher ground than a position.
+func isHighGround(b *bot.Bot, from point.Point, target point.Point) bool {
+	if b.Grid == nil {
+		return false
+	}
+
+	return b.Grid.HeightAt(target) >= b.Grid.HeightAt(from)+highGround
+}
+
+// isSupplyBlocked tells if all of the supply is used before reaching the
+// maximum.
+func isSupplyBlocked(b *bot.Bot) bool {
+	return b.Obs.PlayerCommon.FoodCap < 200 && b.Obs.PlayerCommon.FoodUsed >= b.Obs.PlayerCommon.FoodCap
+}
+
+// supplyDropTarget is a supply depot that didn't receive extra supply yet.
+func supplyDropTarget(b *bot.Bot) *scl.Unit {
+	return b.Units.My.OfType(terran.SupplyDepot, terran.SupplyDepotLowered).Filter(scl.Ready, func(u *scl.Unit) bool {
+		return !u.HasBuff(buff.SupplyDrop)
+	}).First()
+}
+
+// richestMineralField is the mineral field with the most minerals left at a
+// base that's saturated, where a MULE doesn't compete with SCVs.
+func richestMineralField(b *bot.Bot) *scl.Unit {
+	townHalls := b.FindTownHalls().Filter(scl.Ready, filter.IsCcAtExpansion(b.State.CcForExp))
+
+	var mineralFields scl.Units
+	for _, townHall := range townHalls {
+		base := scl.Units{townHall}
+		if b.FindUnsaturatedMineralFieldsNearTownHalls(base).Exists() {
+			continue
+		}
+
+		mineralFields = append(mineralFields, b.FindMineralFieldsNearTownHalls(base)...)
+	}
+
+	return mineralFields.Max(func(u *scl.Unit) float64 {
+		return float64(u.MineralContents)
+	})
+}
@@ -0,0 +1,180 @@
+package micro
+
+import (
+	"slices"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/filter"
+	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+)
+
+const (
+	// scoutAt is when the first worker leaves to scout the enemy.
+	scoutAt = 60 * scl.FPS
+
+	// scoutEvery is how long to wait between two scouting trips.
+	scoutEvery = 120 * scl.FPS
+
+	// scoutArrival is how close a scout must get to a location to see it.
+	scoutArrival = 5
+
+	// scoutRetreat is how far a threatened scout looks for a safe position.
+	scoutRetreat = 15
+)
+
+// scoutingScan is the reason given to the orbital commands for the scans that
+// replace scouting trips.
+const scoutingScan = "the enemy"
+
+// handleScout sends a scout through the enemy's possible start locations and
+// its natural. The first trip is made by a worker, and the next ones are made
+// by reapers or by scanner sweeps.
+func handleScout(b *bot.Bot) {
+	state := &b.State.Scout
+
+	scout := b.Units.ByTag[state.Tag]
+	if scout == nil {
+		state.Tag = 0
+		if b.Loop < max(int(scoutAt), state.Next) {
+			return
+		}
+
+		scout = sendScout(b)
+		if scout == nil {
+			return
+		}
+	}
+
+	if isScoutThreatened(b, scout) {
+		log.Info("Scout %v is threatened, retreating", scout.Point())
+		endScouting(b, scout)
+		return
+	}
+
+	// Skip the start locations that were eliminated since the trip started
+	state.Route = slices.DeleteFunc(state.Route, func(p point.Point) bool {
+		return slices.Contains(b.Locs.EnemyStarts, p) && !slices.Contains(b.State.EnemyStarts, p)
+	})
+
+	for len(state.Route) > 0 && scout.IsCloserThan(scoutArrival, state.Route[0]) {
+		log.Info("Scouted %v", state.Route[0])
+		state.Route = state.Route[1:]
+	}
+
+	if len(state.Route) == 0 {
+		endScouting(b, scout)
+		return
+	}
+
+	if filter.IsNotOrderedToTarget(ability.Move, state.Route[0])(scout) {
+		scout.CommandPos(ability.Move, state.Route[0])
+	}
+}
+
+// sendScout starts a new scouting trip. It prefers reapers, then a worker for
+// the first trip. The next trips are scans made by the orbital commands.
+func sendScout(b *bot.Bot) *scl.Unit {
+	state := &b.State.Scout
+	route := scoutRoute(b)
+	if len(route) == 0 {
+		return nil
+	}
+
+	var scout *scl.Unit
+	inWaves := b.State.AttackWaves.Units(b)
+	if reapers := b.Units.My.OfType(terran.Reaper).Filter(scl.Ready, filter.NotIn(inWaves)); reapers.Exists() {
+		scout = reapers.ClosestTo(route[0])
+	} else if state.Trips == 0 {
+		scout = b.FindIdleOrGatheringWorkers().ClosestTo(route[0])
+	}
+
+	if scout == nil {
+		return nil
+	}
+
+	log.Info("Sending %s to scout %v", b.U.Types[scout.UnitType].Name, route)
+	state.Tag = scout.Tag
+	state.Route = route
+	state.Trips++
+	return scout
+}
+
+// scoutRoute lists the locations to scout: the start locations where the enemy
+// might be, then its natural.
+func scoutRoute(b *bot.Bot) point.Points {
+	route := slices.Clone(b.State.EnemyStarts)
+	if natural, ok := b.EnemyNatural(); ok {
+		route.Add(natural)
+	}
+
+	return route
+}
+
+// scoutScanTarget is the first location of the route that isn't already
+// visible when a scouting trip is due and no reaper can make it.
+func scoutScanTarget(b *bot.Bot) (point.Point, bool) {
+	state := &b.State.Scout
+	if state.Trips == 0 || b.Units.ByTag[state.Tag] != nil || b.Loop < max(int(scoutAt), state.Next) {
+		return 0, false
+	}
+
+	inWaves := b.State.AttackWaves.Units(b)
+	if b.Units.My.OfType(terran.Reaper).Filter(scl.Ready, filter.NotIn(inWaves)).Exists() {
+		return 0, false
+	}
+
+	for _, target := range scoutRoute(b) {
+		if b.Grid == nil || !b.Grid.IsVisible(target) {
+			return target, true
+		}
+	}
+
+	return 0, false
+}
+
+// scannedEnemy counts a scan as a scouting trip and schedules the next one.
+func scannedEnemy(b *bot.Bot) {
+	b.State.Scout.Trips++
+	b.State.Scout.Next = b.Loop + int(scoutEvery)
+}
+
+// isScoutThreatened tells if the scout is hurt or in range of enemies that can
+// attack it. Workers are only a threat when they're attacking the scout.
+func isScoutThreatened(b *bot.Bot, scout *scl.Unit) bool {
+	if scout.Health < scout.HealthMax/2 || !b.IsSafeForWorker(scout.Point()) {
+		return true
+	}
+
+	for _, enemy := range b.Units.Enemy.All() {
+		if enemy.IsWorker() && enemy.EngagedTargetTag == scout.Tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// endScouting sends the scout back home and schedules the next trip. A scout
+// that's in range of enemies first retreats to the closest safe position.
+func endScouting(b *bot.Bot, scout *scl.Unit) {
+	state := &b.State.Scout
+
+	retreat, ok := point.Point(0), false
+	if !b.IsSafeForWorker(scout.Point()) {
+		retreat, ok = b.SafeRetreat(scout, scoutRetreat)
+	}
+
+	if ok {
+		scout.CommandPos(ability.Move, retreat)
+		scout.CommandPosQueue(ability.Move, b.Locs.MyStart)
+	} else {
+		scout.CommandPos(ability.Move, b.Locs.MyStart)
+	}
+	state.Tag = 0
+	state.Route = nil
+	state.Next = b.Loop + int(scoutEvery)
+}
@@ -0,0 +1,179 @@
+package micro
+
+import (
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/filter"
+	"github.com/NatoBoram/BlackCompany/sight"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+)
+
+const (
+	// siegeRange is the range of sieged tanks.
+	siegeRange = 13
+
+	// approachRange is added to the siege range so tanks are sieged by the
+	// time enemies walk into range.
+	approachRange = 2
+
+	// leapDistance is how far ahead of the sieged tanks an advancing tank
+	// goes before sieging in turn.
+	leapDistance = 6
+)
+
+// handleTanks sieges the tanks that aren't in an attack wave when enemies
+// approach and unsieges them once nothing is left in range.
+func handleTanks(b *bot.Bot) {
+	inWaves := b.State.AttackWaves.Units(b)
+	idle := b.Units.My.OfType(terran.SiegeTank, terran.SiegeTankSieged).Filter(filter.NotIn(inWaves))
+	if idle.Empty() {
+		return
+	}
+
+	enemies := siegeTargets(b)
+	for _, tank := range idle {
+		if hasSiegeTarget(tank, enemies) {
+			siege(tank)
+		} else {
+			unsiege(tank)
+		}
+	}
+}
+
+// handleWaveTanks controls the tanks of an attack wave instead of
+// attack-moving them with the rest of the wave. They siege when enemies
+// approach or when the wave holds its position, unsiege when the wave moves on
+// and leapfrog towards enemies that are still out of range.
+func handleWaveTanks(b *bot.Bot, a *bot.AttackWave, tanks scl.Units, home point.Point, decision engagement) {
+	if tanks.Empty() {
+		return
+	}
+
+	enemies := siegeTargets(b)
+
+	switch a.State {
+	case bot.Retreating:
+		for _, tank := range tanks {
+			if isSieged(tank) {
+				unsiege(tank)
+			} else if filter.IsNotOrderedToTarget(ability.Move, home)(tank) {
+				tank.CommandPos(ability.Move, home)
+			}
+		}
+
+	case bot.Regrouping:
+		// Home is a defensive position
+		for _, tank := range tanks.CloserThan(regroupRadius, home) {
+			siege(tank)
+		}
+
+	case bot.Gathering:
+		center := a.Units(b).Center()
+		for _, tank := range tanks {
+			switch {
+			case hasSiegeTarget(tank, enemies):
+				siege(tank)
+			case isSieged(tank):
+				unsiege(tank)
+			case tank.IsFurtherThan(sight.LineOfSightScannerSweep.Float64(), center):
+				if filter.IsNotOrderedToTarget(ability.Move, center)(tank) {
+					tank.CommandPos(ability.Move, center)
+				}
+			}
+		}
+
+	case bot.Moving, bot.Engaging:
+		for _, tank := range tanks {
+			switch {
+			case hasSiegeTarget(tank, enemies), decision == hold:
+				siege(tank)
+			case decision == advance:
+				advanceTank(a, tank)
+			default:
+				leapfrog(a, tank, tanks)
+			}
+		}
+	}
+}
+
+// leapfrog advances the tanks of a wave towards enemies that are out of range
+// one at a time so the others can cover them. The tank that's the furthest
+// behind unsieges once every other tank is sieged, then sieges again once it's
+// ahead of them.
+func leapfrog(a *bot.AttackWave, tank *scl.Unit, tanks scl.Units) {
+	others := tanks.Filter(filter.IsNotTag(tank.Tag))
+	sieged := others.Filter(isSieged)
+
+	if isSieged(tank) {
+		if sieged.Len() == others.Len() && tank == rearmost(a, tanks) {
+			unsiege(tank)
+		}
+		return
+	}
+
+	if sieged.Exists() && isAhead(a, tank, sieged, leapDistance) {
+		siege(tank)
+		return
+	}
+
+	advanceTank(a, tank)
+}
+
+// advanceTank attack-moves a tank towards the target of its wave.
+func advanceTank(a *bot.AttackWave, tank *scl.Unit) {
+	if isSieged(tank) {
+		unsiege(tank)
+		return
+	}
+
+	if filter.IsNotOrderedToTarget(ability.Attack, a.Target)(tank) {
+		tank.CommandPos(ability.Attack, a.Target)
+	}
+}
+
+// siegeTargets are the visible enemies that sieged tanks can shoot.
+func siegeTargets(b *bot.Bot) scl.Units {
+	return visibleEnemies(b).Filter(scl.Ground)
+}
+
+// hasSiegeTarget tells if enemies are in range of a tank, or about to be.
+func hasSiegeTarget(tank *scl.Unit, enemies scl.Units) bool {
+	return enemies.CloserThan(siegeRange+approachRange, tank).Exists()
+}
+
+// isSieged tells if a tank is sieged or about to be.
+func isSieged(tank *scl.Unit) bool {
+	if tank.UnitType == terran.SiegeTankSieged {
+		return !filter.IsOrderedTo(ability.Morph_Unsiege)(tank)
+	}
+
+	return filter.IsOrderedTo(ability.Morph_SiegeMode)(tank)
+}
+
+// siege sieges a tank unless it's already sieged.
+func siege(tank *scl.Unit) {
+	if !isSieged(tank) {
+		tank.Command(ability.Morph_SiegeMode)
+	}
+}
+
+// unsiege unsieges a tank unless it's already mobile.
+func unsiege(tank *scl.Unit) {
+	if isSieged(tank) {
+		tank.Command(ability.Morph_Unsiege)
+	}
+}
+
+// rearmost is the tank that's the furthest from the target of its wave.
+func rearmost(a *bot.AttackWave, tanks scl.Units) *scl.Unit {
+	return tanks.FurthestTo(a.Target)
+}
+
+// isAhead tells if a tank is closer to the target of its wave than every other
+// tank by at least a distance.
+func isAhead(a *bot.AttackWave, tank *scl.Unit, others scl.Units, distance float64) bool {
+	front := others.ClosestTo(a.Target)
+	return tank.Dist(a.Target)+distance <= front.Dist(a.Target)
+}
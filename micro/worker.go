@@ -2,13 +2,14 @@ package micro
 
 import (
 	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/filter"
 	"github.com/NatoBoram/BlackCompany/log"
 	"github.com/aiseeq/s2l/lib/scl"
 )
 
 // handleWorkers handles idle workers
 func handleWorkers(b *bot.Bot) {
-	idle := b.FindWorkers().Filter(scl.Idle)
+	idle := b.FindWorkers().Filter(scl.Idle, filter.IsNotTag(b.State.Scout.Tag))
 	if idle.Empty() {
 		return
 	}
@@ -20,12 +21,15 @@ func handleWorkers(b *bot.Bot) {
 
 	start := idle.Len()
 
-	mineralFields := b.FindUnsaturatedMineralFieldsNearTownHalls(townHalls)
+	// Don't send workers where they would get killed
+	safe := func(u *scl.Unit) bool { return b.IsSafeForWorker(u.Point()) }
+
+	mineralFields := b.FindUnsaturatedMineralFieldsNearTownHalls(townHalls).Filter(safe)
 	if idle.Exists() && mineralFields.Exists() {
 		b.FillMineralsUpTo2(&idle, townHalls, mineralFields)
 	}
 
-	vespeneGeysers := b.FindUnsaturatedVespeneGeysersNearTownHalls(townHalls)
+	vespeneGeysers := b.FindUnsaturatedVespeneGeysersNearTownHalls(townHalls).Filter(safe)
 	if idle.Exists() && vespeneGeysers.Exists() {
 		b.FillGases(&idle, townHalls, vespeneGeysers)
 	}
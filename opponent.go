@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/client"
+)
+
+const (
+	// OpponentComputer plays against the built-in AI.
+	OpponentComputer = "computer"
+
+	// OpponentBot plays against another instance of this bot.
+	OpponentBot = "bot"
+)
+
+// ParseRace parses a race like "zerg", ignoring case.
+func ParseRace(name string) (api.Race, error) {
+	for value, race := range api.Race_name {
+		if value != int32(api.Race_NoRace) && strings.EqualFold(race, name) {
+			return api.Race(value), nil
+		}
+	}
+
+	return api.Race_NoRace, fmt.Errorf("unknown race %q, expected terran, zerg, protoss or random", name)
+}
+
+// ParseDifficulty parses a difficulty of the built-in AI like "veryhard",
+// ignoring case.
+func ParseDifficulty(name string) (api.Difficulty, error) {
+	for value, difficulty := range api.Difficulty_name {
+		if value != int32(api.Difficulty_nil) && strings.EqualFold(difficulty, name) {
+			return api.Difficulty(value), nil
+		}
+	}
+
+	names := make([]string, 0, len(api.Difficulty_name)-1)
+	for value := api.Difficulty_VeryEasy; value <= api.Difficulty_CheatInsane; value++ {
+		names = append(names, strings.ToLower(value.String()))
+	}
+
+	return api.Difficulty_nil, fmt.Errorf("unknown difficulty %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// ParseBuild parses a build of the built-in AI like "rush", ignoring case.
+// "random" picks a random build.
+func ParseBuild(name string) (api.AIBuild, error) {
+	if strings.EqualFold(name, "random") {
+		return api.AIBuild_RandomBuild, nil
+	}
+
+	for value, build := range api.AIBuild_name {
+		if value != int32(api.AIBuild_nil) && strings.EqualFold(build, name) {
+			return api.AIBuild(value), nil
+		}
+	}
+
+	return api.AIBuild_nil, fmt.Errorf("unknown build %q, expected random, rush, timing, power, macro or air", name)
+}
+
+// opponentSetup creates the opponent selected by the flags.
+func opponentSetup(flags Flags) (*api.PlayerSetup, error) {
+	race, err := ParseRace(flags.Race)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(flags.Opponent) {
+	case OpponentComputer:
+		difficulty, err := ParseDifficulty(flags.Difficulty)
+		if err != nil {
+			return nil, err
+		}
+
+		build, err := ParseBuild(flags.Build)
+		if err != nil {
+			return nil, err
+		}
+
+		return client.NewComputer(race, difficulty, build), nil
+
+	case OpponentBot:
+		if race != api.Race_Terran && race != api.Race_Random {
+			return nil, fmt.Errorf("the bot can't play %v, it only plays Terran", race)
+		}
+
+		return client.NewParticipant(api.Race_Terran, "BlackCompany"), nil
+
+	default:
+		return nil, fmt.Errorf("unknown opponent %q, expected %q or %q", flags.Opponent, OpponentComputer, OpponentBot)
+	}
+}
+
+// gameMap is the map selected by the flags, or a random ladder map.
+func gameMap(flags Flags) string {
+	if flags.Map != "" {
+		return flags.Map + ".SC2Map"
+	}
+
+	return random1v1Map()
+}
+
+// hostGame creates a game on a map then joins it. When the opponent is
+// another bot, it's started in its own process before joining.
+func hostGame(config *client.GameConfig, flags Flags, mapPath string, opponent *api.PlayerSetup) (*client.GameConfig, error) {
+	log.Info("Using map %q", mapPath)
+
+	if opponent.Type != api.PlayerType_Participant {
+		config.StartGame(mapPath)
+		return config, nil
+	}
+
+	if !config.CreateGame(mapPath) {
+		return nil, fmt.Errorf("failed to create a game on %q", mapPath)
+	}
+
+	startPort := flags.Port + 2
+	config.SetupPorts(startPort)
+
+	if err := startOpponentBot(flags, flags.Port+1, startPort); err != nil {
+		return nil, fmt.Errorf("failed to start the opponent bot: %w", err)
+	}
+
+	config.JoinGame()
+	return config, nil
+}
+
+// startOpponentBot starts another instance of this bot that launches its own
+// StarCraft II on a port and joins the game as the opponent. It doesn't save
+// recordings, traces or match history so it doesn't overwrite the host's.
+func startOpponentBot(flags Flags, gamePort int, startPort int) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the bot's executable: %w", err)
+	}
+
+	strategy := flags.OpponentStrategy
+	if strategy == "" {
+		strategy = flags.Strategy
+	}
+
+	args := append(slices.Clone(os.Args[1:]),
+		"-GamePort", strconv.Itoa(gamePort),
+		"-StartPort", strconv.Itoa(startPort),
+		"-strategy", strategy,
+		"-record=", "-trace=", "-history=",
+	)
+
+	cmd := exec.Command(exe, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run %q: %w", exe, err)
+	}
+
+	log.Info("Started the opponent bot playing %s on port %d", strategy, gamePort)
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			log.Warn("The opponent bot stopped: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// joinConfig joins a game that was created by another process, like a bot
+// that's hosting a bot-vs-bot game. StarCraft II is launched on the game port
+// unless it's already running there.
+func joinConfig(env *Env, flags Flags, bot *api.PlayerSetup) (*client.GameConfig, error) {
+	config := client.NewGameConfig(bot)
+
+	if env.PROTON_PATH != "" && env.STEAM_COMPAT_DATA_PATH != "" {
+		paths, err := sc2Paths(env)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get StarCraft II paths: %w", err)
+		}
+
+		joinFlags := flags
+		joinFlags.Port = flags.GamePort
+		if err = launchProton(paths, joinFlags); err != nil {
+			return nil, fmt.Errorf("failed to launch StarCraft II using Proton: %w", err)
+		}
+
+		config.Connect(flags.GamePort)
+	} else {
+		client.LaunchPortStart = flags.GamePort
+		config.LaunchStarcraft()
+	}
+
+	log.Info("Joining the game on port %d", flags.GamePort)
+	config.SetupPorts(flags.StartPort)
+	config.JoinGame()
+	return config, nil
+}
+
+// MatchDifficulty is the difficulty of the opponent to save in the match
+// history. Joined games are against other bots, so they have no difficulty.
+func MatchDifficulty(flags Flags, opponent *api.PlayerSetup) api.Difficulty {
+	if flags.LadderServer != "" || flags.GamePort > 0 || opponent.Type != api.PlayerType_Computer {
+		return api.Difficulty_nil
+	}
+
+	return opponent.Difficulty
+}
@@ -0,0 +1,3 @@
+// opponent models the enemy from what the bot scouted. It recognizes the
+// enemy's opening from its structures, their timings and its expansions.
+package opponent
@@ -0,0 +1,341 @@
+package opponent
+
+import (
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/protoss"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+// Timings of the openings, in game loops.
+const (
+	// twelvePool is the latest start of a spawning pool in a 12 pool.
+	twelvePool = 30 * scl.FPS
+
+	// earlyPool is the latest start of a spawning pool that's still early.
+	earlyPool = 45 * scl.FPS
+
+	// earlyZerglings is the latest arrival of zerglings from an early pool.
+	earlyZerglings = 150 * scl.FPS
+
+	// fastExpand is the latest start of a natural in a fast expand.
+	fastExpand = 105 * scl.FPS
+)
+
+// Distances used to recognize proxies.
+const (
+	// mainRadius is the distance from a town hall where structures are
+	// considered to be in its base.
+	mainRadius = 25
+
+	// townHallRadius is how far from its expansion a town hall can be.
+	townHallRadius = 10
+)
+
+var (
+	townHalls = []api.UnitTypeID{
+		terran.CommandCenter, terran.CommandCenterFlying, terran.OrbitalCommand,
+		terran.OrbitalCommandFlying, terran.PlanetaryFortress,
+		zerg.Hatchery, zerg.Lair, zerg.Hive,
+		protoss.Nexus,
+	}
+
+	// production are the structures that produce the first army units.
+	production = []api.UnitTypeID{terran.Barracks, zerg.SpawningPool, protoss.Gateway, protoss.WarpGate}
+
+	// airTech are the structures that unlock or produce air units.
+	airTech = []api.UnitTypeID{
+		terran.Starport, terran.FusionCore,
+		zerg.Spire, zerg.GreaterSpire,
+		protoss.Stargate, protoss.FleetBeacon,
+	}
+)
+
+// Model remembers what was scouted of the enemy and recognizes its opening.
+type Model struct {
+	// structures are the enemy's structures by their tag.
+	structures map[api.UnitTag]structure
+
+	// firstSeen is when each type of enemy unit was first seen.
+	firstSeen map[api.UnitTypeID]int
+
+	// airArmy are the enemy's air army units that were seen.
+	airArmy map[api.UnitTag]bool
+
+	myBases    point.Points
+	enemyStart point.Point
+}
+
+// structure is an enemy structure that was seen.
+type structure struct {
+	unitType api.UnitTypeID
+	pos      point.Point
+
+	// started is the latest loop at which the structure could have started.
+	started int
+}
+
+// NewModel creates a model that hasn't seen anything yet.
+func NewModel() *Model {
+	return &Model{
+		structures: map[api.UnitTag]structure{},
+		firstSeen:  map[api.UnitTypeID]int{},
+		airArmy:    map[api.UnitTag]bool{},
+	}
+}
+
+// Observe remembers the enemies that the bot currently sees.
+func (m *Model) Observe(b *scl.Bot) {
+	m.enemyStart = b.Locs.EnemyStart
+	m.myBases = point.Points{b.Locs.MyStart}
+	if len(b.Locs.MyExps) > 0 {
+		m.myBases.Add(b.Locs.MyExps[0])
+	}
+
+	for _, enemy := range b.Units.Enemy.All() {
+		if _, ok := m.firstSeen[enemy.UnitType]; !ok {
+			m.firstSeen[enemy.UnitType] = b.Loop
+		}
+
+		if enemy.IsFlying && enemy.GroundDPS() > 5 && !enemy.IsStructure() {
+			m.airArmy[enemy.Tag] = true
+		}
+
+		if !enemy.IsStructure() {
+			continue
+		}
+
+		// Finished structures could have been started long before being seen
+		started := b.Loop
+		if data := b.U.Types[enemy.UnitType]; data != nil {
+			started -= int(float64(enemy.BuildProgress) * float64(data.BuildTime))
+		}
+
+		if previous, ok := m.structures[enemy.Tag]; ok {
+			started = min(started, previous.started)
+		}
+
+		m.structures[enemy.Tag] = structure{unitType: enemy.UnitType, pos: enemy.Point(), started: started}
+	}
+}
+
+// Classify recognizes the enemy's opening from everything that was observed.
+func (m *Model) Classify() Classification {
+	c := Classification{
+		Scores: map[Opening]float64{
+			ProxyBarracks: m.proxyBarracks(),
+			TwelvePool:    m.twelvePool(),
+			CannonRush:    m.cannonRush(),
+			FastExpand:    m.fastExpand(),
+			MassAir:       m.massAir(),
+			DarkTemplar:   m.darkTemplar(),
+		},
+	}
+
+	for _, opening := range Openings {
+		if score := c.Scores[opening]; score > c.Confidence {
+			c.Opening = opening
+			c.Confidence = score
+		}
+	}
+
+	return c
+}
+
+// proxyBarracks looks for barracks outside of the enemy's bases.
+func (m *Model) proxyBarracks() float64 {
+	bases := m.enemyBases()
+
+	proxies := 0
+	for _, s := range m.structures {
+		if s.unitType != terran.Barracks {
+			continue
+		}
+
+		if bases.ClosestTo(s.pos).Dist(s.pos) > mainRadius {
+			proxies++
+		}
+	}
+
+	if proxies == 0 {
+		return 0
+	}
+
+	return min(1, 0.55+0.15*float64(proxies))
+}
+
+// twelvePool looks for an early spawning pool or early zerglings.
+func (m *Model) twelvePool() float64 {
+	score := 0.0
+
+	if pool, ok := m.earliest(zerg.SpawningPool); ok {
+		switch {
+		case pool <= int(twelvePool):
+			score = 0.8
+		case pool <= int(earlyPool):
+			score = 0.5
+		}
+	}
+
+	if seen, ok := m.firstSeen[zerg.Zergling]; ok && seen <= int(earlyZerglings) {
+		score += 0.3
+	}
+
+	return min(1, score)
+}
+
+// cannonRush looks for pylons and photon cannons in the bot's bases.
+func (m *Model) cannonRush() float64 {
+	score := 0.0
+
+	for _, s := range m.structures {
+		if !m.inMyBases(s.pos) {
+			continue
+		}
+
+		switch s.unitType {
+		case protoss.PhotonCannon:
+			score += 0.7
+		case protoss.Pylon, protoss.Forge:
+			score += 0.3
+		}
+	}
+
+	return min(1, score)
+}
+
+// fastExpand looks for a natural that's started early or before any
+// production.
+func (m *Model) fastExpand() float64 {
+	expansion, ok := m.earliestExpansion()
+	if !ok {
+		return 0
+	}
+
+	score := 0.0
+	if expansion <= int(fastExpand) {
+		score += 0.6
+	}
+
+	if first, ok := m.earliest(production...); !ok || expansion < first {
+		score += 0.4
+	}
+
+	return min(1, score)
+}
+
+// massAir looks for air tech and air units.
+func (m *Model) massAir() float64 {
+	score := 0.0
+
+	tech := m.count(airTech...)
+	if tech > 0 {
+		score += 0.3 + 0.2*float64(tech-1)
+	}
+
+	score += 0.1 * float64(len(m.airArmy))
+
+	return min(1, score)
+}
+
+// darkTemplar looks for the tech that leads to dark templars.
+func (m *Model) darkTemplar() float64 {
+	if _, ok := m.firstSeen[protoss.DarkTemplar]; ok {
+		return 1
+	}
+
+	if m.count(protoss.DarkShrine) > 0 {
+		return 0.9
+	}
+
+	if m.count(protoss.TwilightCouncil) > 0 {
+		return 0.3
+	}
+
+	return 0
+}
+
+// enemyBases are the enemy's main and the town halls it was seen with.
+func (m *Model) enemyBases() point.Points {
+	bases := point.Points{m.enemyStart}
+	for _, s := range m.structures {
+		if IsTownHall(s.unitType) {
+			bases.Add(s.pos)
+		}
+	}
+
+	return bases
+}
+
+// earliestExpansion is the earliest start of a town hall outside of the
+// enemy's main.
+func (m *Model) earliestExpansion() (int, bool) {
+	earliest, found := 0, false
+	for _, s := range m.structures {
+		if !IsTownHall(s.unitType) || s.pos.IsCloserThan(townHallRadius, m.enemyStart) {
+			continue
+		}
+
+		if !found || s.started < earliest {
+			earliest, found = s.started, true
+		}
+	}
+
+	return earliest, found
+}
+
+// earliest is the earliest start of a structure of the provided types.
+func (m *Model) earliest(types ...api.UnitTypeID) (int, bool) {
+	earliest, found := 0, false
+	for _, s := range m.structures {
+		if !isOneOf(s.unitType, types) {
+			continue
+		}
+
+		if !found || s.started < earliest {
+			earliest, found = s.started, true
+		}
+	}
+
+	return earliest, found
+}
+
+// count counts the structures of the provided types that were seen.
+func (m *Model) count(types ...api.UnitTypeID) int {
+	count := 0
+	for _, s := range m.structures {
+		if isOneOf(s.unitType, types) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// inMyBases tells if a position is in the bot's main or natural.
+func (m *Model) inMyBases(pos point.Point) bool {
+	for _, base := range m.myBases {
+		if pos.IsCloserThan(mainRadius, base) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsTownHall tells if a unit type is a town hall of any race, flying or not.
+func IsTownHall(unitType api.UnitTypeID) bool {
+	return isOneOf(unitType, townHalls)
+}
+
+func isOneOf(unitType api.UnitTypeID, types []api.UnitTypeID) bool {
+	for _, t := range types {
+		if unitType == t {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+package opponent
+
+import "fmt"
+
+// Opening is a recognized enemy opening.
+type Opening int
+
+const (
+	// UnknownOpening means that nothing particular was scouted.
+	UnknownOpening Opening = iota
+
+	// ProxyBarracks is a Terran opening with barracks built away from its main.
+	ProxyBarracks
+
+	// TwelvePool is a Zerg opening with a very early spawning pool.
+	TwelvePool
+
+	// CannonRush is a Protoss opening with pylons and photon cannons built in
+	// the bot's bases.
+	CannonRush
+
+	// FastExpand is an opening where the enemy takes its natural before
+	// building any production.
+	FastExpand
+
+	// MassAir is an opening that goes for air units, like void rays, mutalisks
+	// or banshees.
+	MassAir
+
+	// DarkTemplar is a Protoss opening that goes for cloaked dark templars.
+	DarkTemplar
+)
+
+// Openings are every opening that can be recognized.
+var Openings = []Opening{ProxyBarracks, TwelvePool, CannonRush, FastExpand, MassAir, DarkTemplar}
+
+var openingNames = map[Opening]string{
+	UnknownOpening: "Unknown",
+	ProxyBarracks:  "Proxy Barracks",
+	TwelvePool:     "12 Pool",
+	CannonRush:     "Cannon Rush",
+	FastExpand:     "Fast Expand",
+	MassAir:        "Mass Air",
+	DarkTemplar:    "Dark Templar",
+}
+
+func (o Opening) String() string {
+	if name, ok := openingNames[o]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Opening(%d)", int(o))
+}
+
+// Aggressive tells if the opening attacks early, before the bot's first attack
+// wave is ready.
+func (o Opening) Aggressive() bool {
+	return o == ProxyBarracks || o == TwelvePool || o == CannonRush
+}
+
+// Classification is the most likely opening of the enemy.
+type Classification struct {
+	Opening Opening
+
+	// Confidence is how sure the recognition is, between 0 and 1.
+	Confidence float64
+
+	// Scores are the confidence of every opening that was considered.
+	Scores map[Opening]float64
+}
+
+// Is tells if the enemy is playing an opening with at least the provided
+// confidence. It's not limited to the most likely opening.
+func (c Classification) Is(opening Opening, confidence float64) bool {
+	return c.Scores[opening] >= confidence && c.Scores[opening] > 0
+}
+
+func (c Classification) String() string {
+	if c.Opening == UnknownOpening {
+		return c.Opening.String()
+	}
+
+	return fmt.Sprintf("%v (%.0f%%)", c.Opening, c.Confidence*100)
+}
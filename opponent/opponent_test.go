@@ -0,0 +1,144 @@
+package opponent_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/opponent"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/protoss"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+// recognize plays a scenario and returns the enemy's opening that the bot
+// recognized.
+func recognize(t *testing.T, s *sim.Scenario) opponent.Classification {
+	t.Helper()
+
+	result, err := sim.Run(s.Info, s.Frames(3), &bot.Strategy{Name: "Nothing"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	return result.Bot.State.EnemyOpening
+}
+
+func TestModel_Classify(t *testing.T) {
+	tests := []struct {
+		name     string
+		race     api.Race
+		scenario func(s *sim.Scenario)
+		expected opponent.Opening
+	}{
+		{
+			name: "nothing",
+			race: api.Race_Zerg,
+			scenario: func(s *sim.Scenario) {
+				s.Add(api.Alliance_Enemy, zerg.Hatchery, s.EnemyStart())
+			},
+			expected: opponent.UnknownOpening,
+		},
+		{
+			name: "12 pool",
+			race: api.Race_Zerg,
+			scenario: func(s *sim.Scenario) {
+				s.Loop = 600
+				s.Add(api.Alliance_Enemy, zerg.Hatchery, s.EnemyStart())
+				pool := s.Add(api.Alliance_Enemy, zerg.SpawningPool, s.EnemyStart()-point.Pt(6, 0))
+				pool.BuildProgress = 0.3
+			},
+			expected: opponent.TwelvePool,
+		},
+		{
+			name: "proxy barracks",
+			race: api.Race_Terran,
+			scenario: func(s *sim.Scenario) {
+				s.Add(api.Alliance_Enemy, terran.CommandCenter, s.EnemyStart())
+				s.Add(api.Alliance_Enemy, terran.Barracks, (s.MyStart()+s.EnemyStart())/2)
+			},
+			expected: opponent.ProxyBarracks,
+		},
+		{
+			name: "cannon rush",
+			race: api.Race_Protoss,
+			scenario: func(s *sim.Scenario) {
+				s.Add(api.Alliance_Enemy, protoss.Pylon, s.MyStart()+point.Pt(8, 8))
+				s.Add(api.Alliance_Enemy, protoss.PhotonCannon, s.MyStart()+point.Pt(8, 6))
+			},
+			expected: opponent.CannonRush,
+		},
+		{
+			name: "fast expand",
+			race: api.Race_Protoss,
+			scenario: func(s *sim.Scenario) {
+				s.Loop = 1000
+				s.Add(api.Alliance_Enemy, protoss.Nexus, s.EnemyStart())
+				nexus := s.Add(api.Alliance_Enemy, protoss.Nexus, s.EnemyNatural())
+				nexus.BuildProgress = 0.2
+			},
+			expected: opponent.FastExpand,
+		},
+		{
+			name: "mass air",
+			race: api.Race_Protoss,
+			scenario: func(s *sim.Scenario) {
+				s.Add(api.Alliance_Enemy, protoss.Nexus, s.EnemyStart())
+				s.Add(api.Alliance_Enemy, protoss.Stargate, s.EnemyStart()-point.Pt(6, 0))
+				s.Add(api.Alliance_Enemy, protoss.Stargate, s.EnemyStart()-point.Pt(6, 4))
+			},
+			expected: opponent.MassAir,
+		},
+		{
+			name: "dark templar",
+			race: api.Race_Protoss,
+			scenario: func(s *sim.Scenario) {
+				s.Add(api.Alliance_Enemy, protoss.Nexus, s.EnemyStart())
+				s.Add(api.Alliance_Enemy, protoss.DarkShrine, s.EnemyStart()-point.Pt(6, 0))
+			},
+			expected: opponent.DarkTemplar,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := sim.NewScenario(test.race)
+			test.scenario(s)
+
+			classification := recognize(t, s)
+			if classification.Opening != test.expected {
+				t.Errorf("Opening = %v, expected %v", classification, test.expected)
+			}
+
+			if test.expected != opponent.UnknownOpening && classification.Confidence < 0.5 {
+				t.Errorf("Confidence = %v, expected at least %v", classification.Confidence, 0.5)
+			}
+		})
+	}
+}
+
+func TestClassification_Is(t *testing.T) {
+	c := opponent.Classification{
+		Opening:    opponent.MassAir,
+		Confidence: 0.8,
+		Scores:     map[opponent.Opening]float64{opponent.MassAir: 0.8, opponent.DarkTemplar: 0.6},
+	}
+
+	if !c.Is(opponent.MassAir, 0.5) {
+		t.Errorf("Is(MassAir, 0.5) = %v, expected %v", false, true)
+	}
+
+	if !c.Is(opponent.DarkTemplar, 0.5) {
+		t.Errorf("Is(DarkTemplar, 0.5) = %v, expected %v", false, true)
+	}
+
+	if c.Is(opponent.TwelvePool, 0) {
+		t.Errorf("Is(TwelvePool, 0) = %v, expected %v", true, false)
+	}
+
+	if c.String() != "Mass Air (80%)" {
+		t.Errorf("String() = %q, expected %q", c.String(), "Mass Air (80%)")
+	}
+}
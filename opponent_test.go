@@ -0,0 +1,87 @@
+package main_test
+
+import (
+	"testing"
+
+	main "github.com/NatoBoram/BlackCompany"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+func TestParseRace_IgnoresCase(t *testing.T) {
+	race, err := main.ParseRace("zerg")
+	if err != nil {
+		t.Fatalf("ParseRace() error = %v", err)
+	}
+
+	if race != api.Race_Zerg {
+		t.Errorf("ParseRace() = %v, expected %v", race, api.Race_Zerg)
+	}
+}
+
+func TestParseRace_Unknown(t *testing.T) {
+	if _, err := main.ParseRace("noRace"); err == nil {
+		t.Errorf("ParseRace() error = %v, expected an error", err)
+	}
+}
+
+func TestParseDifficulty_IgnoresCase(t *testing.T) {
+	difficulty, err := main.ParseDifficulty("CHEATINSANE")
+	if err != nil {
+		t.Fatalf("ParseDifficulty() error = %v", err)
+	}
+
+	if difficulty != api.Difficulty_CheatInsane {
+		t.Errorf("ParseDifficulty() = %v, expected %v", difficulty, api.Difficulty_CheatInsane)
+	}
+}
+
+func TestParseDifficulty_Unknown(t *testing.T) {
+	if _, err := main.ParseDifficulty("impossible"); err == nil {
+		t.Errorf("ParseDifficulty() error = %v, expected an error", err)
+	}
+}
+
+func TestParseBuild_Random(t *testing.T) {
+	build, err := main.ParseBuild("random")
+	if err != nil {
+		t.Fatalf("ParseBuild() error = %v", err)
+	}
+
+	if build != api.AIBuild_RandomBuild {
+		t.Errorf("ParseBuild() = %v, expected %v", build, api.AIBuild_RandomBuild)
+	}
+}
+
+func TestParseBuild_Air(t *testing.T) {
+	build, err := main.ParseBuild("air")
+	if err != nil {
+		t.Fatalf("ParseBuild() error = %v", err)
+	}
+
+	if build != api.AIBuild_Air {
+		t.Errorf("ParseBuild() = %v, expected %v", build, api.AIBuild_Air)
+	}
+}
+
+func TestMatchDifficulty(t *testing.T) {
+	computer := &api.PlayerSetup{Type: api.PlayerType_Computer, Difficulty: api.Difficulty_Hard}
+	tests := []struct {
+		name     string
+		flags    main.Flags
+		opponent *api.PlayerSetup
+		expected api.Difficulty
+	}{
+		{name: "computer", flags: main.Flags{}, opponent: computer, expected: api.Difficulty_Hard},
+		{name: "ladder", flags: main.Flags{LadderServer: "127.0.0.1"}, opponent: computer, expected: api.Difficulty_nil},
+		{name: "joined", flags: main.Flags{GamePort: 5678}, opponent: computer, expected: api.Difficulty_nil},
+		{name: "bot", flags: main.Flags{}, opponent: &api.PlayerSetup{Type: api.PlayerType_Participant}, expected: api.Difficulty_nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := main.MatchDifficulty(test.flags, test.opponent); got != test.expected {
+				t.Errorf("MatchDifficulty() = %v, expected %v", got, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/NatoBoram/BlackCompany/agent"
+	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/NatoBoram/BlackCompany/record"
+	"github.com/NatoBoram/BlackCompany/sim"
+)
+
+// playback feeds a recorded game to the bot then reports every frame where the
+// bot didn't do the same thing as in the recording.
+func playback(path string, strategist agent.Strategist) error {
+	recording, err := record.Open(path)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Playing back %d frames from %q", len(recording.Frames), path)
+
+	result, err := sim.Replay(recording, strategist)
+	if err != nil {
+		return fmt.Errorf("failed to replay the recording: %w", err)
+	}
+
+	different := 0
+	for i, frame := range recording.Frames {
+		recorded := recording.Actions[i]
+		replayed := result.Server.ActionsAt(i)
+		if record.SameActions(recorded, replayed) {
+			continue
+		}
+
+		different++
+		log.Info("Frame %d (loop %d): recorded %d actions, replayed %d actions",
+			i, frame.GetObservation().GetGameLoop(), len(recorded), len(replayed))
+	}
+
+	log.Info("%d of %d frames have different actions", different, len(recording.Frames))
+	return nil
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"fmt"
+	"net"
 	"os/exec"
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/NatoBoram/BlackCompany/log"
 	"github.com/aiseeq/s2l/protocol/api"
@@ -59,8 +61,8 @@ func launchProton(paths *Sc2Paths, flags Flags) error {
 		return fmt.Errorf("failed to check if Proton is running: %w", err)
 	}
 
-	if running != nil {
-		log.Info("Proton is already running")
+	if running != nil && listening(flags.Listen, flags.Port) {
+		log.Info("Proton is already running StarCraft II on port %d", flags.Port)
 		return nil
 	}
 
@@ -74,6 +76,18 @@ func launchProton(paths *Sc2Paths, flags Flags) error {
 	return nil
 }
 
+// listening tells if something accepts connections on a port, like an
+// instance of StarCraft II that's already running.
+func listening(host string, port int) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), time.Second)
+	if err != nil {
+		return false
+	}
+
+	conn.Close()
+	return true
+}
+
 func runningProton() (*process.Process, error) {
 	processes, err := process.Processes()
 	if err != nil {
@@ -94,64 +108,21 @@ func runningProton() (*process.Process, error) {
 	return nil, nil
 }
 
-func protonConfig(bot *api.PlayerSetup, participants ...*api.PlayerSetup) *client.GameConfig {
-	mapPath := random1v1Map()
-
-	log.Info("Using map %q\n", mapPath)
-
-	client.SetMap(mapPath)
-
-	participants = append([]*api.PlayerSetup{bot}, participants...)
-	config := client.NewGameConfig(participants...)
-	config.Connect(8168)
-	config.StartGame(mapPath)
-
-	return config
-}
-
 func replayConfig(flags Flags) (*client.GameConfig, error) {
 	if flags.Replay == "" {
-		log.Fatal("no replay file provided")
+		return nil, fmt.Errorf("no replay file provided")
 	}
 
 	if flags.Map == "" {
-		log.Fatal("no map provided")
+		return nil, fmt.Errorf("no map provided for replay %q", flags.Replay)
 	}
 
 	client.SetMap(flags.Map + ".SC2Map")
 
 	observer := client.NewParticipant(api.Race_NoRace, "Observer")
 	config := client.NewGameConfig(observer)
-	config.Connect(8168)
+	config.Connect(flags.Port)
 
 	replayPath := path.Join("C:\\Program Files (x86)\\StarCraft II\\Replays", flags.Replay)
-
-	request := &api.Request{Request: &api.Request_StartReplay{
-		StartReplay: &api.RequestStartReplay{
-			Replay: &api.RequestStartReplay_ReplayPath{
-				ReplayPath: replayPath,
-			},
-			ObservedPlayerId: 1,
-			Options: &api.InterfaceOptions{
-				Raw:   true,
-				Score: true,
-			},
-			DisableFog: false,
-			Realtime:   false,
-		},
-	}}
-
-	response, err := config.Client.Request(request)
-	if err != nil {
-		return nil, fmt.Errorf("failed to start replay: %w", err)
-	}
-
-	if response.GetStartReplay().GetError() != api.ResponseStartReplay_nil {
-		return nil, fmt.Errorf("failed to start replay. Error: %v, Details: %v",
-			response.GetStartReplay().GetError(),
-			response.GetStartReplay().GetErrorDetails(),
-		)
-	}
-
-	return config, nil
+	return startReplay(config, replayPath, flags)
 }
@@ -0,0 +1,7 @@
+// record saves what the bot sees and does during a game to a file so that the
+// game can be fed back to the bot offline.
+//
+// A recording is a gzip stream of entries. Each entry is a one byte kind, the
+// length of the message as an unsigned varint, then the message encoded as
+// protobuf.
+package record
@@ -0,0 +1,178 @@
+package record
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+// Recording is the content of a recording file.
+type Recording struct {
+	// Info is the game info of the recorded game.
+	Info *api.ResponseGameInfo
+
+	// Data is the game data of the recorded game.
+	Data *api.ResponseData
+
+	// Frames are the observations in the order they were received.
+	Frames []*api.ResponseObservation
+
+	// Actions are the actions sent after each frame. It has the same length as
+	// Frames.
+	Actions [][]*api.Action
+}
+
+// Open reads a recording file.
+func Open(path string) (*Recording, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording %q: %w", path, err)
+	}
+	defer file.Close()
+
+	recording, err := Read(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording %q: %w", path, err)
+	}
+
+	return recording, nil
+}
+
+// Read reads a recording from the provided reader.
+func Read(r io.Reader) (*Recording, error) {
+	decompressed, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress recording: %w", err)
+	}
+	defer decompressed.Close()
+
+	reader := bufio.NewReader(decompressed)
+	recording := &Recording{}
+
+	for {
+		kind, err := reader.ReadByte()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read entry kind: %w", err)
+		}
+
+		length, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the length of %v: %w", Kind(kind), err)
+		}
+
+		data := make([]byte, length)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, fmt.Errorf("failed to read %v: %w", Kind(kind), err)
+		}
+
+		if err := recording.add(Kind(kind), data); err != nil {
+			return nil, err
+		}
+	}
+
+	if recording.Info == nil {
+		return nil, fmt.Errorf("recording has no game info")
+	}
+
+	if recording.Data == nil {
+		return nil, fmt.Errorf("recording has no game data")
+	}
+
+	return recording, nil
+}
+
+// add decodes an entry and adds it to the recording.
+func (r *Recording) add(kind Kind, data []byte) error {
+	switch kind {
+	case KindGameInfo:
+		r.Info = &api.ResponseGameInfo{}
+		return decode(kind, data, r.Info)
+
+	case KindData:
+		r.Data = &api.ResponseData{}
+		return decode(kind, data, r.Data)
+
+	case KindObservation:
+		observation := &api.ResponseObservation{}
+		if err := decode(kind, data, observation); err != nil {
+			return err
+		}
+
+		r.Frames = append(r.Frames, observation)
+		r.Actions = append(r.Actions, nil)
+		return nil
+
+	case KindActions:
+		if len(r.Frames) == 0 {
+			return fmt.Errorf("recording has actions before its first observation")
+		}
+
+		request := &api.RequestAction{}
+		if err := decode(kind, data, request); err != nil {
+			return err
+		}
+
+		last := len(r.Actions) - 1
+		r.Actions[last] = append(r.Actions[last], request.Actions...)
+		return nil
+
+	default:
+		return fmt.Errorf("recording has an entry of %v", kind)
+	}
+}
+
+func decode(kind Kind, data []byte, m message) error {
+	if err := m.Unmarshal(data); err != nil {
+		return fmt.Errorf("failed to decode %v: %w", kind, err)
+	}
+
+	return nil
+}
+
+// SameActions tells whether two lists of actions contain the same actions. The
+// order of the actions is ignored since the bot doesn't send its commands in a
+// stable order.
+func SameActions(a []*api.Action, b []*api.Action) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	left, err := encodeActions(a)
+	if err != nil {
+		return false
+	}
+
+	right, err := encodeActions(b)
+	if err != nil {
+		return false
+	}
+
+	slices.Sort(left)
+	slices.Sort(right)
+	return slices.Equal(left, right)
+}
+
+// encodeActions encodes each action so they can be compared.
+func encodeActions(actions []*api.Action) ([]string, error) {
+	encoded := make([]string, 0, len(actions))
+	for _, action := range actions {
+		data, err := action.Marshal()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode action: %w", err)
+		}
+
+		encoded = append(encoded, string(data))
+	}
+
+	return encoded, nil
+}
@@ -0,0 +1,91 @@
+package record_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/record"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+)
+
+func TestRead_RoundTrip(t *testing.T) {
+	var buffer bytes.Buffer
+	w := record.NewWriter(&buffer)
+
+	info := &api.ResponseGameInfo{MapName: "Simulation"}
+	data := &api.ResponseData{Units: []*api.UnitTypeData{{UnitId: 45, Name: "SCV"}}}
+	actions := []*api.Action{{ActionRaw: &api.ActionRaw{Action: &api.ActionRaw_UnitCommand{
+		UnitCommand: &api.ActionRawUnitCommand{AbilityId: ability.Train_SCV, UnitTags: []api.UnitTag{1}},
+	}}}}
+
+	if err := w.WriteGameInfo(info); err != nil {
+		t.Fatalf("WriteGameInfo() error = %v", err)
+	}
+	if err := w.WriteData(data); err != nil {
+		t.Fatalf("WriteData() error = %v", err)
+	}
+	for loop := uint32(0); loop < 3; loop++ {
+		observation := &api.ResponseObservation{Observation: &api.Observation{GameLoop: loop * 16}}
+		if err := w.WriteObservation(observation); err != nil {
+			t.Fatalf("WriteObservation() error = %v", err)
+		}
+	}
+	if err := w.WriteActions(actions); err != nil {
+		t.Fatalf("WriteActions() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	recording, err := record.Read(&buffer)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if recording.Info.MapName != info.MapName {
+		t.Errorf("Info.MapName = %q, expected %q", recording.Info.MapName, info.MapName)
+	}
+	if len(recording.Data.Units) != 1 || recording.Data.Units[0].Name != "SCV" {
+		t.Errorf("Data.Units = %v, expected %v", recording.Data.Units, data.Units)
+	}
+	if len(recording.Frames) != 3 {
+		t.Fatalf("len(Frames) = %d, expected 3", len(recording.Frames))
+	}
+	if loop := recording.Frames[2].Observation.GameLoop; loop != 32 {
+		t.Errorf("Frames[2].Observation.GameLoop = %d, expected 32", loop)
+	}
+	if len(recording.Actions[0]) != 0 || len(recording.Actions[1]) != 0 {
+		t.Errorf("Actions[0:2] = %v, expected no actions", recording.Actions[0:2])
+	}
+	if !record.SameActions(recording.Actions[2], actions) {
+		t.Errorf("Actions[2] = %v, expected %v", recording.Actions[2], actions)
+	}
+}
+
+func TestRead_MissingGameInfo(t *testing.T) {
+	var buffer bytes.Buffer
+	w := record.NewWriter(&buffer)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if _, err := record.Read(&buffer); err == nil {
+		t.Errorf("Read() error = %v, expected an error", err)
+	}
+}
+
+func TestRead_ActionsBeforeObservation(t *testing.T) {
+	var buffer bytes.Buffer
+	w := record.NewWriter(&buffer)
+	if err := w.WriteActions(nil); err != nil {
+		t.Fatalf("WriteActions() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if _, err := record.Read(&buffer); err == nil {
+		t.Errorf("Read() error = %v, expected an error", err)
+	}
+}
@@ -0,0 +1,133 @@
+package record
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+// Kind is the type of message that an entry contains.
+type Kind byte
+
+const (
+	// KindGameInfo is an `api.ResponseGameInfo`.
+	KindGameInfo Kind = iota + 1
+	// KindData is an `api.ResponseData`.
+	KindData
+	// KindObservation is an `api.ResponseObservation`.
+	KindObservation
+	// KindActions is an `api.RequestAction` that was sent after the last
+	// observation.
+	KindActions
+)
+
+// message is a protobuf message as generated by s2l.
+type message interface {
+	Marshal() ([]byte, error)
+	Unmarshal([]byte) error
+}
+
+// Writer writes a recording.
+type Writer struct {
+	file   io.Closer
+	buffer *bufio.Writer
+	gzip   *gzip.Writer
+}
+
+// Create creates a recording file at the provided path.
+func Create(path string) (*Writer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording %q: %w", path, err)
+	}
+
+	w := NewWriter(file)
+	w.file = file
+	return w, nil
+}
+
+// NewWriter creates a recording that's written to the provided writer.
+func NewWriter(w io.Writer) *Writer {
+	buffer := bufio.NewWriter(w)
+	return &Writer{buffer: buffer, gzip: gzip.NewWriter(buffer)}
+}
+
+// WriteGameInfo records the game info.
+func (w *Writer) WriteGameInfo(info *api.ResponseGameInfo) error {
+	return w.write(KindGameInfo, info)
+}
+
+// WriteData records the game data.
+func (w *Writer) WriteData(data *api.ResponseData) error {
+	return w.write(KindData, data)
+}
+
+// WriteObservation records an observation.
+func (w *Writer) WriteObservation(observation *api.ResponseObservation) error {
+	return w.write(KindObservation, observation)
+}
+
+// WriteActions records the actions sent after the last observation.
+func (w *Writer) WriteActions(actions []*api.Action) error {
+	return w.write(KindActions, &api.RequestAction{Actions: actions})
+}
+
+// Close flushes the recording and closes the underlying file, if any.
+func (w *Writer) Close() error {
+	if err := w.gzip.Close(); err != nil {
+		return fmt.Errorf("failed to close recording: %w", err)
+	}
+
+	if err := w.buffer.Flush(); err != nil {
+		return fmt.Errorf("failed to flush recording: %w", err)
+	}
+
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return fmt.Errorf("failed to close recording file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (w *Writer) write(kind Kind, m message) error {
+	data, err := m.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to encode %v: %w", kind, err)
+	}
+
+	header := make([]byte, 1, 1+binary.MaxVarintLen64)
+	header[0] = byte(kind)
+	header = binary.AppendUvarint(header, uint64(len(data)))
+
+	if _, err := w.gzip.Write(header); err != nil {
+		return fmt.Errorf("failed to write %v: %w", kind, err)
+	}
+
+	if _, err := w.gzip.Write(data); err != nil {
+		return fmt.Errorf("failed to write %v: %w", kind, err)
+	}
+
+	return nil
+}
+
+func (k Kind) String() string {
+	switch k {
+	case KindGameInfo:
+		return "game info"
+	case KindData:
+		return "data"
+	case KindObservation:
+		return "observation"
+	case KindActions:
+		return "actions"
+	default:
+		return fmt.Sprintf("unknown kind %d", byte(k))
+	}
+}
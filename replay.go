@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/NatoBoram/BlackCompany/replay"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/client"
+)
+
+// launchObserver launches StarCraft II without starting a game so replays can
+// be started in it.
+func launchObserver(env *Env, flags Flags) (*client.GameConfig, error) {
+	config := client.NewGameConfig(client.NewParticipant(api.Race_NoRace, "Observer"))
+
+	if env.PROTON_PATH != "" && env.STEAM_COMPAT_DATA_PATH != "" {
+		paths, err := sc2Paths(env)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get StarCraft II paths: %w", err)
+		}
+
+		if err = launchProton(paths, flags); err != nil {
+			return nil, fmt.Errorf("failed to launch StarCraft II using Proton: %w", err)
+		}
+
+		config.Connect(flags.Port)
+		return config, nil
+	}
+
+	client.LaunchPortStart = flags.Port
+	config.LaunchStarcraft()
+	return config, nil
+}
+
+// startReplay starts a replay on a game that's already launched.
+func startReplay(config *client.GameConfig, path string, flags Flags) (*client.GameConfig, error) {
+	options := replay.Options{Path: path, ObservedPlayer: flags.ReplayPlayer}
+	if err := replay.Start(config.Client, options); err != nil {
+		return nil, fmt.Errorf("failed to start replay %q: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// analyzeReplay steps through a replay with the bot's perception, prints what
+// each player had at the end and saves the timeline when a path is provided.
+func analyzeReplay(c *client.Client, flags Flags) {
+	timeline := replay.Analyze(c)
+
+	log.Info("Timeline of %s:", flags.Replay)
+	if err := timeline.WriteSummary(os.Stdout); err != nil {
+		log.Warn("Failed to print the timeline: %v", err)
+	}
+
+	if flags.Timeline == "" {
+		return
+	}
+
+	if err := timeline.Save(flags.Timeline); err != nil {
+		log.Error("Failed to save the timeline: %v", err)
+		return
+	}
+
+	log.Info("Saved the timeline to %q", flags.Timeline)
+}
+
+// extractBuildOrders observes every replay of a directory once for each player
+// and saves their build orders.
+func extractBuildOrders(env *Env, flags Flags) error {
+	paths, err := filepath.Glob(filepath.Join(flags.Replays, "*.SC2Replay"))
+	if err != nil {
+		return fmt.Errorf("failed to list the replays in %q: %w", flags.Replays, err)
+	}
+
+	if len(paths) == 0 {
+		return fmt.Errorf("no replays found in %q", flags.Replays)
+	}
+
+	if err := os.MkdirAll(flags.BuildOrders, 0o755); err != nil {
+		return fmt.Errorf("failed to create the build orders directory %q: %w", flags.BuildOrders, err)
+	}
+
+	config, err := launchObserver(env, flags)
+	if err != nil {
+		return err
+	}
+
+	for i, path := range paths {
+		log.Info("Extracting build orders from %q (%d/%d)", path, i+1, len(paths))
+
+		for _, player := range []int{1, 2} {
+			flags.ReplayPlayer = player
+			if _, err := startReplay(config, sc2ReplayPath(env, path), flags); err != nil {
+				log.Error("Failed to observe player %d: %v", player, err)
+				continue
+			}
+
+			order := replay.Analyze(config.Client).ObservedBuildOrder()
+			order.Replay = filepath.Base(path)
+			saveBuildOrder(order, flags.BuildOrders)
+		}
+	}
+
+	return nil
+}
+
+// sc2ReplayPath is the path of a replay as seen by StarCraft II. Proton sees
+// the Linux file system as the Z: drive.
+func sc2ReplayPath(env *Env, path string) string {
+	if env.PROTON_PATH == "" {
+		return path
+	}
+
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		absolute = path
+	}
+
+	return "Z:" + strings.ReplaceAll(absolute, "/", "\\")
+}
+
+// saveBuildOrder saves a build order as JSON and, when it has steps that the
+// bot can play, as a strategy file.
+func saveBuildOrder(order *replay.BuildOrder, dir string) {
+	name := fmt.Sprintf("%s.p%d", strings.TrimSuffix(order.Replay, filepath.Ext(order.Replay)), order.Player)
+
+	path := filepath.Join(dir, name+".json")
+	if err := order.Save(path); err != nil {
+		log.Error("Failed to save the build order: %v", err)
+		return
+	}
+
+	log.Info("Saved %d entries of %s's build order to %q", len(order.Entries), order.Name, path)
+
+	file := order.StrategyFile()
+	if len(file.Steps) == 0 {
+		return
+	}
+
+	path = filepath.Join(dir, name+".yaml")
+	if err := file.Save(path); err != nil {
+		log.Error("Failed to save the strategy: %v", err)
+		return
+	}
+
+	log.Info("Saved %s to %q", file.Name, path)
+}
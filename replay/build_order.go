@@ -0,0 +1,365 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/protoss"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+// Kinds of build order entries.
+const (
+	KindUnit      = "unit"
+	KindStructure = "structure"
+	KindUpgrade   = "upgrade"
+)
+
+// BuildOrder is what a player started during a replay, in order.
+type BuildOrder struct {
+	// Replay is the name of the replay file.
+	Replay string `json:"replay"`
+
+	Map       string `json:"map"`
+	Player    int    `json:"player"`
+	Name      string `json:"name"`
+	Race      string `json:"race"`
+	EnemyRace string `json:"enemyRace"`
+
+	Entries []BuildEntry `json:"entries"`
+}
+
+// BuildEntry is a unit, structure or upgrade that was started.
+type BuildEntry struct {
+	// Supply is the player's supply when the entry was started.
+	Supply int `json:"supply"`
+
+	// Loop is the game loop when the entry was started.
+	Loop int `json:"loop"`
+
+	// Time is the game time when the entry was started, like "1:23".
+	Time string `json:"time"`
+
+	// Kind is either "unit", "structure" or "upgrade".
+	Kind string `json:"kind"`
+
+	// Name is the name of the unit type or upgrade, like "Barracks" or
+	// "Stimpack".
+	Name string `json:"name"`
+
+	// Ability is the ability that started the entry, like "Research_Stimpack".
+	Ability string `json:"ability,omitempty"`
+}
+
+// ignoredUnits are units that appear without being built.
+var ignoredUnits = map[api.UnitTypeID]bool{
+	terran.AutoTurret:           true,
+	terran.KD8Charge:            true,
+	terran.MULE:                 true,
+	zerg.Broodling:              true,
+	zerg.BroodlingEscort:        true,
+	zerg.Changeling:             true,
+	zerg.ChangelingMarine:       true,
+	zerg.ChangelingZealot:       true,
+	zerg.ChangelingMarineShield: true,
+	zerg.ChangelingZergling:     true,
+	zerg.CreepTumor:             true,
+	zerg.CreepTumorBurrowed:     true,
+	zerg.CreepTumorQueen:        true,
+	zerg.Egg:                    true,
+	zerg.Larva:                  true,
+	zerg.LocustMP:               true,
+	zerg.LocustMPFlying:         true,
+	protoss.AdeptPhaseShift:     true,
+	protoss.DisruptorPhased:     true,
+	protoss.Interceptor:         true,
+}
+
+// track adds the units, structures and upgrades that the observed player
+// started since the last observation. What the player had when the replay
+// started isn't part of its build order.
+func (t *Timeline) track(b *bot.Bot) {
+	first := t.units == nil
+	if first {
+		t.units = map[api.UnitTag]api.UnitTypeID{}
+		t.upgrades = map[api.UpgradeID]bool{}
+	}
+
+	for _, unit := range b.Units.MyAll {
+		unitType := canonicalType(b, unit.UnitType)
+		if previous, ok := t.units[unit.Tag]; ok && previous == unitType {
+			continue
+		}
+
+		t.units[unit.Tag] = unitType
+		if first || ignoredUnits[unitType] || !knownType(b, unitType) {
+			continue
+		}
+
+		// Units that appear by themselves can't be started
+		data := b.U.Types[unitType]
+		if data.AbilityId == 0 {
+			continue
+		}
+		kind := KindUnit
+		if unit.IsStructure() {
+			kind = KindStructure
+		}
+
+		// Units that are already finished started building a while ago
+		loop := b.Loop
+		if unit.BuildProgress == 1 {
+			loop = max(0, b.Loop-int(data.BuildTime))
+		}
+
+		t.BuildOrder = append(t.BuildOrder, BuildEntry{
+			Loop:    loop,
+			Kind:    kind,
+			Name:    data.Name,
+			Ability: ability.String(data.AbilityId),
+		})
+	}
+
+	for _, id := range b.Obs.RawData.Player.UpgradeIds {
+		if t.upgrades[id] {
+			continue
+		}
+
+		t.upgrades[id] = true
+		if first || int(id) >= len(b.U.Upgrades) || b.U.Upgrades[id] == nil {
+			continue
+		}
+
+		data := b.U.Upgrades[id]
+		t.BuildOrder = append(t.BuildOrder, BuildEntry{
+			Loop:    max(0, b.Loop-int(data.ResearchTime)),
+			Kind:    KindUpgrade,
+			Name:    data.Name,
+			Ability: ability.String(data.AbilityId),
+		})
+	}
+}
+
+// canonicalType is the type of a unit regardless of its current mode, like a
+// lowered supply depot or a sieged tank.
+func canonicalType(b *bot.Bot, unitType api.UnitTypeID) api.UnitTypeID {
+	if !knownType(b, unitType) {
+		return unitType
+	}
+
+	if alias := b.U.Types[unitType].UnitAlias; alias != 0 {
+		return alias
+	}
+
+	return unitType
+}
+
+// knownType tells if the game data has the unit type.
+func knownType(b *bot.Bot, unitType api.UnitTypeID) bool {
+	return int(unitType) < len(b.U.Types) && b.U.Types[unitType] != nil
+}
+
+// ObservedBuildOrder is the build order of the observed player, sorted by when
+// each entry was started.
+func (t *Timeline) ObservedBuildOrder() *BuildOrder {
+	order := &BuildOrder{
+		Map:     t.Map,
+		Player:  t.ObservedPlayer,
+		Entries: slices.Clone(t.BuildOrder),
+	}
+
+	for _, player := range t.Players {
+		if player.Id == t.ObservedPlayer {
+			order.Name = player.Name
+			order.Race = player.Race
+		} else {
+			order.EnemyRace = player.Race
+		}
+	}
+
+	slices.SortStableFunc(order.Entries, func(a, b BuildEntry) int {
+		return a.Loop - b.Loop
+	})
+
+	player := t.Player(t.ObservedPlayer)
+	for i := range order.Entries {
+		entry := &order.Entries[i]
+		entry.Time = macro.GameTime(entry.Loop)
+		if player != nil {
+			entry.Supply = player.supplyAt(entry.Loop)
+		}
+	}
+
+	return order
+}
+
+// supplyAt is the player's supply at a game loop.
+func (p *Player) supplyAt(loop int) int {
+	supply := 0
+	for _, snapshot := range p.Snapshots {
+		if snapshot.Loop > loop {
+			break
+		}
+
+		supply = snapshot.Supply
+	}
+
+	return supply
+}
+
+// Save writes the build order to a file as indented JSON.
+func (o *BuildOrder) Save(path string) error {
+	data, err := json.MarshalIndent(o, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to encode build order: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write build order file %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// researchers are the structures that research Terran upgrades, by the
+// beginning of their ability's name.
+var researchers = []struct {
+	prefix   string
+	building string
+}{
+	{"Research_TerranInfantry", "EngineeringBay"},
+	{"Research_HiSecAutoTracking", "EngineeringBay"},
+	{"Research_TerranStructureArmorUpgrade", "EngineeringBay"},
+	{"Research_TerranVehicle", "Armory"},
+	{"Research_TerranShip", "Armory"},
+	{"Research_Stimpack", "BarracksTechLab"},
+	{"Research_CombatShield", "BarracksTechLab"},
+	{"Research_ConcussiveShells", "BarracksTechLab"},
+	{"Research_InfernalPreigniter", "FactoryTechLab"},
+	{"Research_SmartServos", "FactoryTechLab"},
+	{"Research_DrillingClaws", "FactoryTechLab"},
+	{"Research_CycloneLockOnDamage", "FactoryTechLab"},
+	{"Research_BansheeCloakingField", "StarportTechLab"},
+	{"Research_BansheeHyperflightRotors", "StarportTechLab"},
+	{"Research_RavenCorvidReactor", "StarportTechLab"},
+	{"Research_PersonalCloaking", "GhostAcademy"},
+	{"Research_BattlecruiserWeaponRefit", "FusionCore"},
+}
+
+// structureRequirements are the structures that must be finished before a
+// Terran structure can be built.
+var structureRequirements = map[string][]string{
+	"Barracks":      {"SupplyDepot"},
+	"Bunker":        {"Barracks"},
+	"Factory":       {"Barracks"},
+	"GhostAcademy":  {"Barracks"},
+	"Starport":      {"Factory"},
+	"Armory":        {"Factory"},
+	"FusionCore":    {"Starport"},
+	"MissileTurret": {"EngineeringBay"},
+	"SensorTower":   {"EngineeringBay"},
+}
+
+// StrategyFile turns the build order into a strategy file that can be played
+// with `-strategy-file`. Each step waits for the supply at which the player
+// started it. Only Terran structures, upgrades and marines have matching build
+// steps, so everything else is left out and other races get no steps at all.
+func (o *BuildOrder) StrategyFile() macro.StrategyFile {
+	file := macro.StrategyFile{Name: o.strategyName()}
+	if o.Race != api.Race_Terran.String() {
+		return file
+	}
+
+	if race, ok := api.Race_value[o.EnemyRace]; ok && api.Race(race) != api.Race_NoRace && api.Race(race) != api.Race_Random {
+		file.EnemyRaces = []string{o.EnemyRace}
+	}
+
+	counts := map[string]int{}
+	townHalls := 1
+	for _, entry := range o.Entries {
+		counts[entry.Name]++
+
+		var step macro.StepFile
+		switch {
+		case entry.Kind == KindUnit && entry.Name == "Marine" && counts[entry.Name] == 1:
+			step = macro.StepFile{Kind: "marine"}
+
+		case entry.Kind == KindUpgrade:
+			building := researcher(entry.Ability)
+			if building == "" {
+				continue
+			}
+
+			step = macro.StepFile{Kind: "upgrade", Building: building, Ability: entry.Ability}
+
+		case entry.Kind != KindStructure:
+			continue
+
+		case entry.Name == "CommandCenter":
+			townHalls++
+			step = macro.StepFile{Kind: "expand", Quantity: townHalls}
+
+		case entry.Name == "OrbitalCommand":
+			step = macro.StepFile{Kind: "orbitalCommand", Quantity: counts[entry.Name]}
+
+		case entry.Name == "PlanetaryFortress":
+			step = macro.StepFile{Kind: "planetaryFortress"}
+
+		case entry.Name == "Refinery" || entry.Name == "RefineryRich":
+			step = macro.StepFile{Kind: "refinery", Quantity: counts["Refinery"] + counts["RefineryRich"]}
+
+		case strings.HasSuffix(entry.Name, "Reactor") || strings.HasSuffix(entry.Name, "TechLab"):
+			building := strings.TrimSuffix(strings.TrimSuffix(entry.Name, "Reactor"), "TechLab")
+			step = macro.StepFile{Kind: "addon", Unit: entry.Name, Building: building, Quantity: counts[entry.Name]}
+
+		case strings.HasPrefix(entry.Ability, "Build_"):
+			step = macro.StepFile{
+				Kind:     "building",
+				Unit:     entry.Name,
+				Quantity: counts[entry.Name],
+				Requires: structureRequirements[entry.Name],
+			}
+
+		default:
+			continue
+		}
+
+		step.Supply = entry.Supply
+		file.Steps = append(file.Steps, step)
+	}
+
+	return file
+}
+
+// strategyName names the strategy after the player and the replay.
+func (o *BuildOrder) strategyName() string {
+	name := o.Name
+	if name == "" {
+		name = fmt.Sprintf("Player %d", o.Player)
+	}
+
+	if o.Replay == "" {
+		return name
+	}
+
+	return fmt.Sprintf("%s (%s)", name, strings.TrimSuffix(o.Replay, ".SC2Replay"))
+}
+
+// researcher finds the structure that researches an upgrade.
+func researcher(abilityName string) string {
+	for _, r := range researchers {
+		if strings.HasPrefix(abilityName, r.prefix) {
+			return r.building
+		}
+	}
+
+	return ""
+}
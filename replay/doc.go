@@ -0,0 +1,4 @@
+// replay steps through StarCraft II replays with the bot's perception and
+// exports what each player had over time, so lost games and pro games can be
+// studied to derive build orders.
+package replay
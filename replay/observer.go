@@ -0,0 +1,204 @@
+package replay
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/client"
+)
+
+// Options are the settings used to start a replay.
+type Options struct {
+	// Path is the path of the replay file, as seen by StarCraft II.
+	Path string
+
+	// ObservedPlayer is the player whose point of view is used by the bot's
+	// perception.
+	ObservedPlayer int
+}
+
+// Start starts a replay on a client that's connected to StarCraft II. The fog
+// of war is disabled so every player's units end up in the timeline.
+func Start(c *client.Client, options Options) error {
+	response, err := c.StartReplay(api.RequestStartReplay{
+		Replay:           &api.RequestStartReplay_ReplayPath{ReplayPath: options.Path},
+		ObservedPlayerId: api.PlayerID(options.ObservedPlayer),
+		Options: &api.InterfaceOptions{
+			Raw:   true,
+			Score: true,
+		},
+		DisableFog: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start replay: %w", err)
+	}
+
+	if response.GetError() != api.ResponseStartReplay_nil {
+		return fmt.Errorf("failed to start replay. Error: %v, Details: %v", response.GetError(), response.GetErrorDetails())
+	}
+
+	return nil
+}
+
+// Analyze steps through a replay that was started on the client with the bot's
+// perception and returns the timeline of each player.
+func Analyze(c *client.Client) *Timeline {
+	b := bot.New(c)
+
+	stop := make(chan struct{})
+	b.Init(stop)
+	b.Observe()
+	b.InitState()
+	b.DetectEnemyRace()
+
+	timeline := newTimeline(b)
+	log.Info("Analyzing a replay on %s from the point of view of player %d", timeline.Map, timeline.ObservedPlayer)
+
+	for b.Client.Status == api.Status_in_replay {
+		b.Step()
+
+		// Only look at the frames that the bot parsed
+		if b.LastLoop == b.Loop {
+			claimTownHalls(b)
+			timeline.observe(b)
+		}
+
+		// The replay can't be influenced
+		b.Actions = nil
+
+		step := api.RequestStep{Count: uint32(b.FramesPerOrder)}
+		if _, err := b.Client.Step(step); err != nil {
+			log.Error("An unknown error occurred while stepping: %v", err)
+			break
+		}
+
+		b.Observe()
+	}
+
+	stop <- struct{}{}
+	return timeline
+}
+
+// newTimeline creates an empty timeline for every player of the replay.
+func newTimeline(b *bot.Bot) *Timeline {
+	t := &Timeline{
+		Map:            b.Info.MapName,
+		ObservedPlayer: int(b.Obs.PlayerCommon.PlayerId),
+	}
+
+	for _, info := range b.Info.PlayerInfo {
+		if info.Type == api.PlayerType_Observer {
+			continue
+		}
+
+		race := info.RaceActual
+		if race == api.Race_NoRace {
+			race = info.RaceRequested
+		}
+
+		t.Players = append(t.Players, &Player{
+			Id:   int(info.PlayerId),
+			Name: info.PlayerName,
+			Race: race.String(),
+		})
+	}
+
+	return t
+}
+
+// claimTownHalls marks the observed player's town halls as being at their
+// expansion so their bases are watched by `FindEnemiesInBases`. The bot only
+// does this for the command centers it plans itself.
+func claimTownHalls(b *bot.Bot) {
+	for _, townHall := range b.FindTownHalls() {
+		if townHall.IsFlying {
+			continue
+		}
+
+		if _, ok := b.State.CcForExp[townHall.Tag]; !ok {
+			b.State.CcForExp[townHall.Tag] = townHall.Point()
+		}
+	}
+}
+
+// observe adds a snapshot of every player and what the perception found.
+func (t *Timeline) observe(b *bot.Bot) {
+	t.Loops = max(t.Loops, b.Loop)
+
+	for _, player := range t.Players {
+		player.add(snapshot(b, player.Id))
+	}
+
+	t.track(b)
+
+	// The same enemy can be found more than once in a base
+	enemiesInBases := map[api.UnitTag]struct{}{}
+	for _, enemies := range b.FindEnemiesInBases() {
+		for _, enemy := range enemies {
+			enemiesInBases[enemy.Tag] = struct{}{}
+		}
+	}
+
+	t.perceive(Perception{
+		Loop:           b.Loop,
+		EnemyRace:      b.EnemyRace.String(),
+		EnemiesInBases: len(enemiesInBases),
+		EnemyAirArmy:   b.FindEnemyAirArmy().Len(),
+		EnemyClusters:  len(b.Enemies.Clusters),
+	})
+}
+
+// snapshot counts what a player has. The observed player's supply comes from
+// the game while the other players' supply is added up from their units.
+func snapshot(b *bot.Bot, playerId int) *Snapshot {
+	s := &Snapshot{
+		Loop:       b.Loop,
+		Units:      map[string]int{},
+		Structures: map[string]int{},
+	}
+
+	var supply, supplyCap float32
+	for _, unit := range b.Obs.RawData.Units {
+		if int(unit.Owner) != playerId || unit.DisplayType == api.DisplayType_Snapshot {
+			continue
+		}
+
+		if !knownType(b, unit.UnitType) {
+			continue
+		}
+
+		data := b.U.Types[unit.UnitType]
+		if b.U.Attributes[unit.UnitType][api.Attribute_Structure] {
+			s.Structures[data.Name]++
+		} else {
+			s.Units[data.Name]++
+		}
+
+		supply += data.FoodRequired
+		if unit.BuildProgress == 1 {
+			supplyCap += data.FoodProvided
+		}
+	}
+
+	s.Supply = int(supply)
+	s.SupplyCap = int(min(supplyCap, 200))
+
+	if int(b.Obs.PlayerCommon.PlayerId) != playerId {
+		return s
+	}
+
+	s.Supply = b.FoodUsed
+	s.SupplyCap = b.FoodCap
+
+	for _, id := range b.Obs.RawData.Player.UpgradeIds {
+		if int(id) < len(b.U.Upgrades) && b.U.Upgrades[id] != nil {
+			s.Upgrades = append(s.Upgrades, b.U.Upgrades[id].Name)
+		}
+	}
+	slices.Sort(s.Upgrades)
+
+	return s
+}
@@ -0,0 +1,256 @@
+package replay_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/replay"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/upgrade"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+// analyze plays a scenario as a replay and returns its timeline.
+func analyze(t *testing.T, scenario func() (*api.ResponseGameInfo, []*api.ResponseObservation)) *replay.Timeline {
+	t.Helper()
+
+	info, frames := scenario()
+	server := sim.NewServer(info, sim.Data(), frames)
+	defer server.Close()
+
+	c, err := server.Connect()
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	if err := replay.Start(c, replay.Options{Path: "Simulation.SC2Replay", ObservedPlayer: 1}); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	return replay.Analyze(c)
+}
+
+// scenario is a replay where zerglings run into the observed player's main and a
+// mutalisk spawns at the enemy's main.
+func scenario() (*api.ResponseGameInfo, []*api.ResponseObservation) {
+	s := sim.NewScenario(api.Race_Zerg)
+	s.Add(api.Alliance_Enemy, zerg.Hatchery, s.EnemyStart())
+	frames := s.Frames(3)
+
+	s.Upgrades = []api.UpgradeID{upgrade.Stimpack}
+	for i := 0; i < 4; i++ {
+		s.Add(api.Alliance_Enemy, zerg.Zergling, s.MyStart()+point.Pt(3, float64(i)))
+	}
+	frames = append(frames, s.Frames(3)...)
+
+	s.Add(api.Alliance_Enemy, zerg.Mutalisk, s.EnemyStart())
+	return s.Info, append(frames, s.Frames(3)...)
+}
+
+func TestAnalyze_Players(t *testing.T) {
+	timeline := analyze(t, scenario)
+
+	if timeline.ObservedPlayer != 1 {
+		t.Errorf("ObservedPlayer = %d, expected 1", timeline.ObservedPlayer)
+	}
+
+	if len(timeline.Players) != 2 {
+		t.Fatalf("len(Players) = %d, expected 2", len(timeline.Players))
+	}
+
+	me := timeline.Player(1).Last()
+	if me.Units["SCV"] != 12 || me.Structures["CommandCenter"] != 1 {
+		t.Errorf("Units = %v, Structures = %v, expected 12 SCVs and a command center", me.Units, me.Structures)
+	}
+	if me.Supply != 12 || me.SupplyCap != 15 {
+		t.Errorf("Supply = %d/%d, expected 12/15", me.Supply, me.SupplyCap)
+	}
+	if !slices.Equal(me.Upgrades, []string{"Stimpack"}) {
+		t.Errorf("Upgrades = %v, expected [Stimpack]", me.Upgrades)
+	}
+
+	enemy := timeline.Player(2)
+	if enemy.Race != api.Race_Zerg.String() {
+		t.Errorf("Race = %s, expected %v", enemy.Race, api.Race_Zerg)
+	}
+
+	last := enemy.Last()
+	if last.Units["Zergling"] != 4 || last.Units["Mutalisk"] != 1 || last.Structures["Hatchery"] != 1 {
+		t.Errorf("Units = %v, Structures = %v, expected 4 zerglings, a mutalisk and a hatchery", last.Units, last.Structures)
+	}
+	if last.Supply != 4 || last.SupplyCap != 6 {
+		t.Errorf("Supply = %d/%d, expected 4/6", last.Supply, last.SupplyCap)
+	}
+	if len(last.Upgrades) != 0 {
+		t.Errorf("Upgrades = %v, expected none for the other player", last.Upgrades)
+	}
+}
+
+func TestAnalyze_SnapshotsOnlyChanges(t *testing.T) {
+	timeline := analyze(t, scenario)
+
+	enemy := timeline.Player(2)
+	if len(enemy.Snapshots) != 3 {
+		t.Fatalf("len(Snapshots) = %d, expected 3", len(enemy.Snapshots))
+	}
+
+	for i := 1; i < len(enemy.Snapshots); i++ {
+		if enemy.Snapshots[i].Loop <= enemy.Snapshots[i-1].Loop {
+			t.Errorf("Snapshots[%d].Loop = %d, expected after %d", i, enemy.Snapshots[i].Loop, enemy.Snapshots[i-1].Loop)
+		}
+	}
+}
+
+func TestAnalyze_Perception(t *testing.T) {
+	timeline := analyze(t, scenario)
+
+	if len(timeline.Perception) == 0 {
+		t.Fatalf("len(Perception) = 0, expected more than 0")
+	}
+
+	first := timeline.Perception[0]
+	if first.EnemiesInBases != 0 || first.EnemyAirArmy != 0 {
+		t.Errorf("Perception[0] = %+v, expected no enemies in bases and no air army", first)
+	}
+
+	last := timeline.Perception[len(timeline.Perception)-1]
+	if last.EnemyRace != api.Race_Zerg.String() {
+		t.Errorf("EnemyRace = %s, expected %v", last.EnemyRace, api.Race_Zerg)
+	}
+	if last.EnemiesInBases != 4 {
+		t.Errorf("EnemiesInBases = %d, expected 4", last.EnemiesInBases)
+	}
+	if last.EnemyAirArmy != 1 {
+		t.Errorf("EnemyAirArmy = %d, expected 1", last.EnemyAirArmy)
+	}
+	if last.EnemyClusters == 0 {
+		t.Errorf("EnemyClusters = 0, expected more than 0")
+	}
+}
+
+func TestTimeline_WriteCSV(t *testing.T) {
+	timeline := analyze(t, scenario)
+
+	var buffer bytes.Buffer
+	if err := timeline.WriteCSV(&buffer); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	expected := []string{"player", "loop", "supply", "supply_cap", "kind", "name", "count"}
+	if !slices.Equal(records[0], expected) {
+		t.Errorf("header = %v, expected %v", records[0], expected)
+	}
+
+	found := slices.ContainsFunc(records, func(record []string) bool {
+		return record[0] == "1" && record[4] == "upgrade" && record[5] == "Stimpack"
+	})
+	if !found {
+		t.Errorf("records = %v, expected the Stimpack upgrade of player 1", records)
+	}
+}
+
+// opening is a replay where the observed player builds a supply depot, a
+// barracks, a marine then researches stimpack.
+func opening() (*api.ResponseGameInfo, []*api.ResponseObservation) {
+	s := sim.NewScenario(api.Race_Zerg)
+	frames := s.Frames(2)
+
+	depot := s.Add(api.Alliance_Self, terran.SupplyDepot, s.MyStart()+point.Pt(5, 5))
+	depot.BuildProgress = 0.1
+	frames = append(frames, s.Frames(2)...)
+
+	barracks := s.Add(api.Alliance_Self, terran.Barracks, s.MyStart()+point.Pt(8, 0))
+	barracks.BuildProgress = 0.1
+	frames = append(frames, s.Frames(2)...)
+
+	depot.BuildProgress = 1
+	depot.UnitType = terran.SupplyDepotLowered
+	barracks.BuildProgress = 1
+	s.Loop = 1000
+	s.Add(api.Alliance_Self, terran.Marine, s.MyStart()+point.Pt(8, -3))
+	frames = append(frames, s.Frames(2)...)
+
+	s.Loop = 3000
+	s.Upgrades = []api.UpgradeID{upgrade.Stimpack}
+	return s.Info, append(frames, s.Frames(2)...)
+}
+
+func TestTimeline_ObservedBuildOrder(t *testing.T) {
+	order := analyze(t, opening).ObservedBuildOrder()
+
+	expected := []replay.BuildEntry{
+		{Supply: 12, Loop: 32, Time: "0:01", Kind: replay.KindStructure, Name: "SupplyDepot", Ability: "Build_SupplyDepot"},
+		{Supply: 12, Loop: 64, Time: "0:02", Kind: replay.KindStructure, Name: "Barracks", Ability: "Build_Barracks"},
+		{Supply: 12, Loop: 600, Time: "0:26", Kind: replay.KindUnit, Name: "Marine", Ability: "Train_Marine"},
+		{Supply: 13, Loop: 1400, Time: "1:02", Kind: replay.KindUpgrade, Name: "Stimpack", Ability: "Research_Stimpack"},
+	}
+	if !slices.Equal(order.Entries, expected) {
+		t.Errorf("Entries = %+v, expected %+v", order.Entries, expected)
+	}
+
+	if order.Race != api.Race_Terran.String() || order.EnemyRace != api.Race_Zerg.String() {
+		t.Errorf("Race = %s, EnemyRace = %s, expected Terran against Zerg", order.Race, order.EnemyRace)
+	}
+}
+
+func TestBuildOrder_StrategyFile(t *testing.T) {
+	order := analyze(t, opening).ObservedBuildOrder()
+	order.Replay = "Opening.SC2Replay"
+
+	file := order.StrategyFile()
+	if file.Name != "BlackCompany (Opening)" {
+		t.Errorf("Name = %q, expected %q", file.Name, "BlackCompany (Opening)")
+	}
+
+	kinds := make([]string, 0, len(file.Steps))
+	for _, step := range file.Steps {
+		kinds = append(kinds, step.Kind)
+	}
+
+	expected := []string{"building", "building", "marine", "upgrade"}
+	if !slices.Equal(kinds, expected) {
+		t.Errorf("kinds = %v, expected %v", kinds, expected)
+	}
+
+	if upgrade := file.Steps[3]; upgrade.Building != "BarracksTechLab" || upgrade.Supply != 13 {
+		t.Errorf("Steps[3] = %+v, expected Research_Stimpack in a BarracksTechLab at 13 supply", upgrade)
+	}
+
+	strategy, err := file.Strategy()
+	if err != nil {
+		t.Fatalf("Strategy() error = %v", err)
+	}
+
+	if !strategy.IsMeantFor(api.Race_Zerg) || strategy.IsMeantFor(api.Race_Protoss) {
+		t.Errorf("EnemyRaces = %v, expected [Zerg]", strategy.EnemyRaces)
+	}
+}
+
+func TestBuildOrder_StrategyFileSkipsOtherRaces(t *testing.T) {
+	order := replay.BuildOrder{
+		Replay:    "Protoss.SC2Replay",
+		Name:      "Protoss",
+		Race:      api.Race_Protoss.String(),
+		EnemyRace: api.Race_Terran.String(),
+		Entries: []replay.BuildEntry{
+			{Supply: 14, Kind: replay.KindStructure, Name: "Pylon", Ability: "Build_Pylon"},
+			{Supply: 16, Kind: replay.KindStructure, Name: "Gateway", Ability: "Build_Gateway"},
+			{Supply: 17, Kind: replay.KindStructure, Name: "Assimilator", Ability: "Build_Assimilator"},
+			{Supply: 20, Kind: replay.KindUpgrade, Name: "WarpGateResearch", Ability: "Research_WarpGate"},
+		},
+	}
+
+	if steps := order.StrategyFile().Steps; len(steps) != 0 {
+		t.Errorf("Steps = %+v, expected none", steps)
+	}
+}
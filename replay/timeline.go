@@ -0,0 +1,265 @@
+package replay
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+// Timeline is what each player had during a replay.
+type Timeline struct {
+	Map string `json:"map"`
+
+	// ObservedPlayer is the player whose point of view was used by the bot's
+	// perception.
+	ObservedPlayer int `json:"observedPlayer"`
+
+	// Loops is the length of the replay in game loops.
+	Loops int `json:"loops"`
+
+	Players []*Player `json:"players"`
+
+	// Perception is what the bot's perception thought of the observed player's
+	// situation. A new entry is added every time it changes.
+	Perception []Perception `json:"perception"`
+
+	// BuildOrder is what the observed player started, in the order it was
+	// noticed. See `Timeline.ObservedBuildOrder` for the actual order.
+	BuildOrder []BuildEntry `json:"buildOrder"`
+
+	// units are the types of the observed player's units by their tag.
+	units map[api.UnitTag]api.UnitTypeID
+
+	// upgrades are the observed player's finished upgrades.
+	upgrades map[api.UpgradeID]bool
+}
+
+// Player is the timeline of a single player.
+type Player struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	Race string `json:"race"`
+
+	// Snapshots are what the player had. A new snapshot is added every time
+	// something changes.
+	Snapshots []*Snapshot `json:"snapshots"`
+}
+
+// Snapshot is what a player had at a moment of the replay. Structures and
+// units that are still being built are counted.
+type Snapshot struct {
+	Loop int `json:"loop"`
+
+	Supply    int `json:"supply"`
+	SupplyCap int `json:"supplyCap"`
+
+	Units      map[string]int `json:"units"`
+	Structures map[string]int `json:"structures"`
+
+	// Upgrades are only known for the observed player.
+	Upgrades []string `json:"upgrades,omitempty"`
+}
+
+// Perception is what the bot's perception found at a moment of the replay.
+type Perception struct {
+	Loop int `json:"loop"`
+
+	EnemyRace      string `json:"enemyRace"`
+	EnemiesInBases int    `json:"enemiesInBases"`
+	EnemyAirArmy   int    `json:"enemyAirArmy"`
+	EnemyClusters  int    `json:"enemyClusters"`
+}
+
+// Player finds a player by its ID.
+func (t *Timeline) Player(id int) *Player {
+	for _, player := range t.Players {
+		if player.Id == id {
+			return player
+		}
+	}
+
+	return nil
+}
+
+// Last is the latest snapshot of the player, or nil if it has none.
+func (p *Player) Last() *Snapshot {
+	if len(p.Snapshots) == 0 {
+		return nil
+	}
+
+	return p.Snapshots[len(p.Snapshots)-1]
+}
+
+// add adds a snapshot when it's different from the latest one.
+func (p *Player) add(s *Snapshot) {
+	if last := p.Last(); last != nil && last.equal(s) {
+		return
+	}
+
+	p.Snapshots = append(p.Snapshots, s)
+}
+
+// equal tells if two snapshots have the same content, regardless of when they
+// were taken.
+func (s *Snapshot) equal(other *Snapshot) bool {
+	return s.Supply == other.Supply &&
+		s.SupplyCap == other.SupplyCap &&
+		maps.Equal(s.Units, other.Units) &&
+		maps.Equal(s.Structures, other.Structures) &&
+		slices.Equal(s.Upgrades, other.Upgrades)
+}
+
+// perceive adds what the perception found when it changed.
+func (t *Timeline) perceive(p Perception) {
+	if n := len(t.Perception); n > 0 {
+		last := t.Perception[n-1]
+		last.Loop = p.Loop
+		if last == p {
+			return
+		}
+	}
+
+	t.Perception = append(t.Perception, p)
+}
+
+// Save writes the timeline to a file. The format is CSV when the file ends
+// with ".csv" and JSON otherwise.
+func (t *Timeline) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create timeline file %q: %w", path, err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		err = t.WriteCSV(file)
+	} else {
+		err = t.WriteJSON(file)
+	}
+
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close timeline file %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// WriteJSON writes the timeline as indented JSON.
+func (t *Timeline) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+
+	if err := encoder.Encode(t); err != nil {
+		return fmt.Errorf("failed to write timeline as JSON: %w", err)
+	}
+
+	return nil
+}
+
+// WriteCSV writes the timeline with one row per unit type, structure type and
+// upgrade of each snapshot. The perception isn't included.
+func (t *Timeline) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"player", "loop", "supply", "supply_cap", "kind", "name", "count"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write timeline header: %w", err)
+	}
+
+	for _, player := range t.Players {
+		for _, snapshot := range player.Snapshots {
+			prefix := []string{
+				strconv.Itoa(player.Id),
+				strconv.Itoa(snapshot.Loop),
+				strconv.Itoa(snapshot.Supply),
+				strconv.Itoa(snapshot.SupplyCap),
+			}
+
+			var records [][]string
+			for _, kind := range []struct {
+				name   string
+				counts map[string]int
+			}{{"unit", snapshot.Units}, {"structure", snapshot.Structures}} {
+				for _, name := range slices.Sorted(maps.Keys(kind.counts)) {
+					records = append(records, []string{kind.name, name, strconv.Itoa(kind.counts[name])})
+				}
+			}
+			for _, name := range snapshot.Upgrades {
+				records = append(records, []string{"upgrade", name, "1"})
+			}
+
+			for _, record := range records {
+				if err := writer.Write(append(slices.Clone(prefix), record...)); err != nil {
+					return fmt.Errorf("failed to write timeline of player %d: %w", player.Id, err)
+				}
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write timeline as CSV: %w", err)
+	}
+
+	return nil
+}
+
+// WriteSummary writes a human-readable table of what each player had at the
+// end of the replay.
+func (t *Timeline) WriteSummary(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(table, "Player\tRace\tSnapshots\tPeak supply\tUnits\tStructures\tUpgrades\n")
+	for _, player := range t.Players {
+		peak := 0
+		for _, snapshot := range player.Snapshots {
+			peak = max(peak, snapshot.Supply)
+		}
+
+		units, structures, upgrades := 0, 0, 0
+		if last := player.Last(); last != nil {
+			units = total(last.Units)
+			structures = total(last.Structures)
+			upgrades = len(last.Upgrades)
+		}
+
+		fmt.Fprintf(table, "%d %s\t%s\t%d\t%d\t%d\t%d\t%d\n",
+			player.Id, player.Name, player.Race,
+			len(player.Snapshots), peak,
+			units, structures, upgrades,
+		)
+	}
+
+	fmt.Fprintf(table, "\nLength: %s\n", macro.GameTime(t.Loops))
+
+	if err := table.Flush(); err != nil {
+		return fmt.Errorf("failed to write timeline summary: %w", err)
+	}
+
+	return nil
+}
+
+// total sums the counts of every type.
+func total(counts map[string]int) int {
+	sum := 0
+	for _, count := range counts {
+		sum += count
+	}
+
+	return sum
+}
@@ -0,0 +1,127 @@
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+)
+
+// armyStrategy trains an army composition.
+func armyStrategy(t *testing.T, army ...macro.ArmyUnitFile) *bot.Strategy {
+	t.Helper()
+
+	file := macro.StrategyFile{
+		Name:  "Army",
+		Steps: []macro.StepFile{{Kind: "army", Army: army}},
+	}
+
+	strategy, err := file.Strategy()
+	if err != nil {
+		t.Fatalf("Strategy() error = %v", err)
+	}
+
+	return strategy
+}
+
+// addProduction adds a production building with an add-on.
+func addProduction(s *sim.Scenario, building api.UnitTypeID, addon api.UnitTypeID, pos point.Point) *api.Unit {
+	u := s.Add(api.Alliance_Self, building, pos)
+	if addon != 0 {
+		u.AddOnTag = s.Add(api.Alliance_Self, addon, pos+point.Pt(2.5, -0.5)).Tag
+	}
+
+	return u
+}
+
+func TestRun_ArmyStepDoubleQueuesReactors(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	s.Minerals = 500
+	addProduction(s, terran.Barracks, terran.BarracksReactor, s.MyStart()+point.Pt(8, 8))
+
+	result, err := sim.Run(s.Info, s.Frames(1), armyStrategy(t, macro.ArmyUnitFile{Unit: "Marine", Ratio: 1}))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Both marines are sent in a single command that repeats the barracks
+	var queued int
+	for _, command := range result.CommandsWith(ability.Train_Marine) {
+		queued += len(command.UnitTags)
+	}
+
+	if queued != 2 {
+		t.Errorf("queued marines = %d, expected %d", queued, 2)
+	}
+}
+
+func TestRun_ArmyStepRespectsAddOns(t *testing.T) {
+	army := []macro.ArmyUnitFile{
+		{Unit: "SiegeTank", Count: 2},
+		{Unit: "Marine", Ratio: 4},
+		{Unit: "Marauder", Ratio: 1},
+	}
+
+	tests := []struct {
+		name     string
+		building api.UnitTypeID
+		addon    api.UnitTypeID
+		ability  api.AbilityID
+		trained  bool
+	}{
+		{"tank with tech lab", terran.Factory, terran.FactoryTechLab, ability.Train_SiegeTank, true},
+		{"tank without tech lab", terran.Factory, 0, ability.Train_SiegeTank, false},
+		{"marauder without tech lab", terran.Barracks, 0, ability.Train_Marauder, false},
+		{"marine without tech lab", terran.Barracks, 0, ability.Train_Marine, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := sim.NewScenario(api.Race_Zerg)
+			s.Minerals = 500
+			s.Vespene = 500
+			addProduction(s, test.building, test.addon, s.MyStart()+point.Pt(8, 8))
+
+			result, err := sim.Run(s.Info, s.Frames(1), armyStrategy(t, army...))
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			if got := len(result.CommandsWith(test.ability)) > 0; got != test.trained {
+				t.Errorf("trained = %v, expected %v", got, test.trained)
+			}
+		})
+	}
+}
+
+func TestRun_ArmyStepFollowsRatios(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	s.Minerals = 500
+	s.Vespene = 500
+	s.Add(api.Alliance_Self, terran.SupplyDepot, s.MyStart()+point.Pt(-8, 8))
+	for i := 0; i < 4; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, s.MyStart()+point.Pt(6, float64(i)))
+	}
+	addProduction(s, terran.Barracks, terran.BarracksTechLab, s.MyStart()+point.Pt(8, 8))
+
+	result, err := sim.Run(s.Info, s.Frames(1), armyStrategy(t,
+		macro.ArmyUnitFile{Unit: "Marine", Ratio: 4},
+		macro.ArmyUnitFile{Unit: "Marauder", Ratio: 1},
+	))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got := len(result.CommandsWith(ability.Train_Marauder)); got != 1 {
+		t.Errorf("len(CommandsWith(Train_Marauder)) = %d, expected %d", got, 1)
+	}
+
+	if got := len(result.CommandsWith(ability.Train_Marine)); got != 0 {
+		t.Errorf("len(CommandsWith(Train_Marine)) = %d, expected %d", got, 0)
+	}
+}
@@ -0,0 +1,208 @@
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/influence"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+func TestRun_AttackWaveMovesToTarget(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	for i := 0; i < 6; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, s.MyStart()+point.Pt(4, float64(i)))
+	}
+
+	target := s.EnemyStart()
+	strategy := sim.Setup(func(b *bot.Bot) {
+		marines := b.Units.My.OfType(terran.Marine)
+		b.State.AttackWaves = append(b.State.AttackWaves, bot.AttackWave{Tags: marines.Tags(), Target: target})
+	})
+
+	result, err := sim.Run(s.Info, s.Frames(3), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	commands := result.CommandsWith(ability.Attack)
+	if len(commands) == 0 {
+		t.Fatalf("len(CommandsWith(Attack)) = 0, expected more than 0")
+	}
+
+	for _, command := range commands {
+		if got := point.Pt2(command.GetTargetWorldSpacePos()); got != target {
+			t.Errorf("Attack target = %v, expected %v", got, target)
+		}
+	}
+}
+
+func TestRun_AttackWaveTargetsVisibleBuildings(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	for i := 0; i < 6; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, s.EnemyNatural()+point.Pt(2, float64(i)))
+	}
+	hatchery := s.Add(api.Alliance_Enemy, zerg.Hatchery, s.EnemyStart())
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		marines := b.Units.My.OfType(terran.Marine)
+		b.State.AttackWaves = append(b.State.AttackWaves, bot.AttackWave{Tags: marines.Tags(), Target: marines.Center()})
+	})
+
+	result, err := sim.Run(s.Info, s.Frames(4), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	targeted := false
+	for _, command := range result.CommandsWith(ability.Attack) {
+		if point.Pt2(command.GetTargetWorldSpacePos()) == point.Pt3(hatchery.Pos) {
+			targeted = true
+		}
+	}
+
+	if !targeted {
+		t.Errorf("targeted = %v, expected %v", targeted, true)
+	}
+}
+
+func TestRun_AttackWaveRetreatsFromStrongerArmy(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	middle := (s.MyStart() + s.EnemyStart()) / 2
+	for i := 0; i < 6; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, middle+point.Pt(0, float64(i)))
+	}
+	for i := 0; i < 10; i++ {
+		s.Add(api.Alliance_Enemy, zerg.Roach, middle+point.Pt(6, float64(i)))
+	}
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		marines := b.Units.My.OfType(terran.Marine)
+		b.State.AttackWaves = append(b.State.AttackWaves, bot.AttackWave{Tags: marines.Tags(), Target: s.EnemyStart()})
+	})
+
+	result, err := sim.Run(s.Info, s.Frames(3), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if commands := result.CommandsWith(ability.Attack); len(commands) != 0 {
+		t.Errorf("len(CommandsWith(Attack)) = %d, expected %d", len(commands), 0)
+	}
+
+	commands := result.CommandsWith(ability.Move)
+	if len(commands) == 0 {
+		t.Fatalf("len(CommandsWith(Move)) = 0, expected more than 0")
+	}
+
+	for _, command := range commands {
+		if got := point.Pt2(command.GetTargetWorldSpacePos()); !got.IsCloserThan(middle.Dist(s.MyStart()), s.MyStart()) {
+			t.Errorf("Move target = %v, expected closer to %v than %v", got, s.MyStart(), middle)
+		}
+	}
+}
+
+func TestRun_AttackWaveRetreatsAroundThreats(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	middle := (s.MyStart() + s.EnemyStart()) / 2
+	for i := 0; i < 6; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, middle+point.Pt(0, float64(i)))
+	}
+	for i := 0; i < 10; i++ {
+		s.Add(api.Alliance_Enemy, zerg.Roach, middle+point.Pt(6, float64(i)))
+	}
+
+	// A spine crawler covers the straight way home
+	s.Add(api.Alliance_Enemy, zerg.SpineCrawler, middle.Towards(s.MyStart(), 14))
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		marines := b.Units.My.OfType(terran.Marine)
+		b.State.AttackWaves = append(b.State.AttackWaves, bot.AttackWave{Tags: marines.Tags(), Target: s.EnemyStart()})
+	})
+
+	result, err := sim.Run(s.Info, s.Frames(3), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	commands := result.CommandsWith(ability.Move)
+	if len(commands) == 0 {
+		t.Fatalf("len(CommandsWith(Move)) = 0, expected more than 0")
+	}
+
+	for _, command := range commands {
+		if got := point.Pt2(command.GetTargetWorldSpacePos()); !result.Bot.Influence.IsSafe(influence.Ground, got) {
+			t.Errorf("Move target = %v, expected a safe position", got)
+		}
+	}
+}
+
+func TestRun_AttackWaveEngagesWeakerArmy(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	middle := (s.MyStart() + s.EnemyStart()) / 2
+	for i := 0; i < 6; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, middle+point.Pt(0, float64(i)))
+	}
+	for i := 0; i < 2; i++ {
+		s.Add(api.Alliance_Enemy, zerg.Zergling, middle+point.Pt(6, float64(i)))
+	}
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		marines := b.Units.My.OfType(terran.Marine)
+		b.State.AttackWaves = append(b.State.AttackWaves, bot.AttackWave{Tags: marines.Tags(), Target: s.EnemyStart()})
+	})
+
+	result, err := sim.Run(s.Info, s.Frames(3), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if commands := result.CommandsWith(ability.Attack); len(commands) == 0 {
+		t.Errorf("len(CommandsWith(Attack)) = 0, expected more than 0")
+	}
+
+	if commands := result.CommandsWith(ability.Move); len(commands) != 0 {
+		t.Errorf("len(CommandsWith(Move)) = %d, expected %d", len(commands), 0)
+	}
+}
+
+func TestRun_RetreatingWaveRegroupsWithFreshUnits(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	var tags scl.Tags
+	for i := 0; i < 3; i++ {
+		marine := s.Add(api.Alliance_Self, terran.Marine, s.MyStart()+point.Pt(7, 7+float64(i)/2))
+		tags = append(tags, marine.Tag)
+	}
+	for i := 0; i < 3; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, s.MyStart()+point.Pt(5, 8+float64(i)/2))
+	}
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		wave := bot.AttackWave{Tags: tags, Target: s.EnemyStart(), State: bot.Retreating, Size: 6}
+		b.State.AttackWaves = append(b.State.AttackWaves, wave)
+	})
+
+	result, err := sim.Run(s.Info, s.Frames(4), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	waves := result.Bot.State.AttackWaves
+	if len(waves) != 1 {
+		t.Fatalf("len(AttackWaves) = %d, expected %d", len(waves), 1)
+	}
+
+	if got := waves[0].Tags.Len(); got != 6 {
+		t.Errorf("len(Tags) = %d, expected %d", got, 6)
+	}
+
+	if got := waves[0].State; got == bot.Retreating || got == bot.Regrouping {
+		t.Errorf("State = %v, expected the wave to go out again", got)
+	}
+}
@@ -0,0 +1,279 @@
+package sim
+
+import (
+	"slices"
+
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/buff"
+	"github.com/aiseeq/s2l/protocol/enums/neutral"
+	"github.com/aiseeq/s2l/protocol/enums/protoss"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/upgrade"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+// maxUnitTypeID is bigger than any unit type ID known by s2l. The library
+// indexes unit types by their ID, so every ID must exist in the data.
+const maxUnitTypeID = 2100
+
+// maxUpgradeID is bigger than any upgrade ID known by s2l.
+const maxUpgradeID = 400
+
+var (
+	armored    = api.Attribute_Armored
+	biological = api.Attribute_Biological
+	light      = api.Attribute_Light
+	mechanical = api.Attribute_Mechanical
+	structure  = api.Attribute_Structure
+)
+
+// unitTypes is the subset of the game's unit data that's needed to run the
+// bot's logic. Values are taken from the game's data at normal speed.
+var unitTypes = []*api.UnitTypeData{
+	// Terran units
+	{UnitId: terran.SCV, Name: "SCV", Race: api.Race_Terran, AbilityId: ability.Train_SCV, MineralCost: 50, FoodRequired: 1, CargoSize: 1, BuildTime: 272, SightRange: 8, MovementSpeed: 2.8125, Attributes: []api.Attribute{light, biological, mechanical}, Weapons: []*api.Weapon{groundWeapon(5, 1, 0.1, 1.07)}},
+	{UnitId: terran.MULE, Name: "MULE", Race: api.Race_Terran, SightRange: 8, MovementSpeed: 2.8125, Attributes: []api.Attribute{light, mechanical}},
+	{UnitId: terran.Marine, Name: "Marine", Race: api.Race_Terran, AbilityId: ability.Train_Marine, MineralCost: 50, FoodRequired: 1, CargoSize: 1, BuildTime: 400, SightRange: 9, MovementSpeed: 2.25, Attributes: []api.Attribute{light, biological}, Weapons: []*api.Weapon{anyWeapon(6, 1, 5, 0.61)}},
+	{UnitId: terran.Marauder, Name: "Marauder", Race: api.Race_Terran, AbilityId: ability.Train_Marauder, MineralCost: 100, VespeneCost: 25, FoodRequired: 2, CargoSize: 2, BuildTime: 480, SightRange: 10, MovementSpeed: 2.25, Attributes: []api.Attribute{armored, biological}, Weapons: []*api.Weapon{groundWeapon(10, 1, 6, 1.07)}},
+	{UnitId: terran.Reaper, Name: "Reaper", Race: api.Race_Terran, AbilityId: ability.Train_Reaper, MineralCost: 50, VespeneCost: 50, FoodRequired: 1, CargoSize: 1, BuildTime: 512, SightRange: 9, MovementSpeed: 3.75, Attributes: []api.Attribute{light, biological}, Weapons: []*api.Weapon{groundWeapon(4, 2, 5, 0.79)}},
+	{UnitId: terran.Hellion, Name: "Hellion", Race: api.Race_Terran, AbilityId: ability.Train_Hellion, MineralCost: 100, FoodRequired: 2, CargoSize: 2, BuildTime: 480, SightRange: 10, MovementSpeed: 4.13, Attributes: []api.Attribute{light, mechanical}, Weapons: []*api.Weapon{groundWeapon(8, 1, 5, 1.79)}},
+	{UnitId: terran.SiegeTank, Name: "SiegeTank", Race: api.Race_Terran, AbilityId: ability.Train_SiegeTank, MineralCost: 150, VespeneCost: 125, FoodRequired: 3, CargoSize: 4, BuildTime: 720, SightRange: 11, MovementSpeed: 2.25, Attributes: []api.Attribute{armored, mechanical}, Weapons: []*api.Weapon{groundWeapon(15, 1, 7, 1.04)}},
+	{UnitId: terran.SiegeTankSieged, Name: "SiegeTankSieged", Race: api.Race_Terran, UnitAlias: terran.SiegeTank, FoodRequired: 3, SightRange: 11, Attributes: []api.Attribute{armored, mechanical}, Weapons: []*api.Weapon{groundWeapon(40, 1, 13, 2.14)}},
+	{UnitId: terran.Medivac, Name: "Medivac", Race: api.Race_Terran, AbilityId: ability.Train_Medivac, MineralCost: 100, VespeneCost: 100, FoodRequired: 2, BuildTime: 672, SightRange: 11, MovementSpeed: 3.5, Attributes: []api.Attribute{armored, mechanical}},
+	{UnitId: terran.VikingFighter, Name: "VikingFighter", Race: api.Race_Terran, AbilityId: ability.Train_VikingFighter, MineralCost: 150, VespeneCost: 75, FoodRequired: 2, BuildTime: 672, SightRange: 10, MovementSpeed: 3.85, Attributes: []api.Attribute{armored, mechanical}, Weapons: []*api.Weapon{airWeapon(10, 2, 9, 1.43)}},
+
+	// Terran structures
+	{UnitId: terran.CommandCenter, Name: "CommandCenter", Race: api.Race_Terran, AbilityId: ability.Build_CommandCenter, MineralCost: 400, FoodProvided: 15, BuildTime: 1590, SightRange: 11, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.CommandCenterFlying, Name: "CommandCenterFlying", Race: api.Race_Terran, UnitAlias: terran.CommandCenter, FoodProvided: 15, SightRange: 11, MovementSpeed: 0.94, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.OrbitalCommand, Name: "OrbitalCommand", Race: api.Race_Terran, TechAlias: []api.UnitTypeID{terran.CommandCenter}, AbilityId: ability.Morph_OrbitalCommand, MineralCost: 550, FoodProvided: 15, BuildTime: 560, SightRange: 11, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.OrbitalCommandFlying, Name: "OrbitalCommandFlying", Race: api.Race_Terran, TechAlias: []api.UnitTypeID{terran.CommandCenter}, UnitAlias: terran.OrbitalCommand, FoodProvided: 15, SightRange: 11, MovementSpeed: 0.94, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.PlanetaryFortress, Name: "PlanetaryFortress", Race: api.Race_Terran, TechAlias: []api.UnitTypeID{terran.CommandCenter}, AbilityId: ability.Morph_PlanetaryFortress, MineralCost: 550, VespeneCost: 150, FoodProvided: 15, BuildTime: 806, SightRange: 11, Attributes: []api.Attribute{armored, mechanical, structure}, Weapons: []*api.Weapon{groundWeapon(40, 1, 6, 1.43)}},
+	{UnitId: terran.SupplyDepot, Name: "SupplyDepot", Race: api.Race_Terran, AbilityId: ability.Build_SupplyDepot, MineralCost: 100, FoodProvided: 8, BuildTime: 480, SightRange: 9, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.SupplyDepotLowered, Name: "SupplyDepotLowered", Race: api.Race_Terran, UnitAlias: terran.SupplyDepot, FoodProvided: 8, SightRange: 9, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.Refinery, Name: "Refinery", Race: api.Race_Terran, AbilityId: ability.Build_Refinery, MineralCost: 75, BuildTime: 480, SightRange: 9, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.RefineryRich, Name: "RefineryRich", Race: api.Race_Terran, TechAlias: []api.UnitTypeID{terran.Refinery}, SightRange: 9, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.Barracks, Name: "Barracks", Race: api.Race_Terran, AbilityId: ability.Build_Barracks, MineralCost: 150, BuildTime: 880, SightRange: 9, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.Factory, Name: "Factory", Race: api.Race_Terran, AbilityId: ability.Build_Factory, MineralCost: 150, VespeneCost: 100, BuildTime: 960, SightRange: 9, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.Starport, Name: "Starport", Race: api.Race_Terran, AbilityId: ability.Build_Starport, MineralCost: 150, VespeneCost: 100, BuildTime: 800, SightRange: 9, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.EngineeringBay, Name: "EngineeringBay", Race: api.Race_Terran, AbilityId: ability.Build_EngineeringBay, MineralCost: 125, BuildTime: 560, SightRange: 9, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.Armory, Name: "Armory", Race: api.Race_Terran, AbilityId: ability.Build_Armory, MineralCost: 150, VespeneCost: 100, BuildTime: 1040, SightRange: 9, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.MissileTurret, Name: "MissileTurret", Race: api.Race_Terran, AbilityId: ability.Build_MissileTurret, MineralCost: 100, BuildTime: 400, SightRange: 11, Attributes: []api.Attribute{armored, mechanical, structure}, Weapons: []*api.Weapon{airWeapon(12, 2, 7, 0.86)}},
+	{UnitId: terran.TechLab, Name: "TechLab", Race: api.Race_Terran, SightRange: 9, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.Reactor, Name: "Reactor", Race: api.Race_Terran, SightRange: 9, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.BarracksTechLab, Name: "BarracksTechLab", Race: api.Race_Terran, AbilityId: ability.Build_TechLab_Barracks, MineralCost: 50, VespeneCost: 25, BuildTime: 400, SightRange: 9, TechAlias: []api.UnitTypeID{terran.TechLab}, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.BarracksReactor, Name: "BarracksReactor", Race: api.Race_Terran, AbilityId: ability.Build_Reactor_Barracks, MineralCost: 50, VespeneCost: 50, BuildTime: 800, SightRange: 9, TechAlias: []api.UnitTypeID{terran.Reactor}, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.FactoryTechLab, Name: "FactoryTechLab", Race: api.Race_Terran, AbilityId: ability.Build_TechLab_Factory, MineralCost: 50, VespeneCost: 25, BuildTime: 400, SightRange: 9, TechAlias: []api.UnitTypeID{terran.TechLab}, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.FactoryReactor, Name: "FactoryReactor", Race: api.Race_Terran, AbilityId: ability.Build_Reactor_Factory, MineralCost: 50, VespeneCost: 50, BuildTime: 800, SightRange: 9, TechAlias: []api.UnitTypeID{terran.Reactor}, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.StarportTechLab, Name: "StarportTechLab", Race: api.Race_Terran, AbilityId: ability.Build_TechLab_Starport, MineralCost: 50, VespeneCost: 25, BuildTime: 400, SightRange: 9, TechAlias: []api.UnitTypeID{terran.TechLab}, Attributes: []api.Attribute{armored, mechanical, structure}},
+	{UnitId: terran.StarportReactor, Name: "StarportReactor", Race: api.Race_Terran, AbilityId: ability.Build_Reactor_Starport, MineralCost: 50, VespeneCost: 50, BuildTime: 800, SightRange: 9, TechAlias: []api.UnitTypeID{terran.Reactor}, Attributes: []api.Attribute{armored, mechanical, structure}},
+
+	// Zerg
+	{UnitId: zerg.Drone, Name: "Drone", Race: api.Race_Zerg, AbilityId: ability.Train_Drone, MineralCost: 50, FoodRequired: 1, BuildTime: 272, SightRange: 8, MovementSpeed: 2.8125, Attributes: []api.Attribute{light, biological}, Weapons: []*api.Weapon{groundWeapon(5, 1, 0.1, 1.07)}},
+	{UnitId: zerg.Zergling, Name: "Zergling", Race: api.Race_Zerg, AbilityId: ability.Train_Zergling, MineralCost: 25, FoodRequired: 0.5, BuildTime: 336, SightRange: 8, MovementSpeed: 2.95, Attributes: []api.Attribute{light, biological}, Weapons: []*api.Weapon{groundWeapon(5, 1, 0.1, 0.497)}},
+	{UnitId: zerg.Baneling, Name: "Baneling", Race: api.Race_Zerg, MineralCost: 25, VespeneCost: 25, FoodRequired: 0.5, SightRange: 8, MovementSpeed: 2.5, Attributes: []api.Attribute{biological}, Weapons: []*api.Weapon{groundWeapon(16, 1, 0.25, 0.833)}},
+	{UnitId: zerg.Roach, Name: "Roach", Race: api.Race_Zerg, AbilityId: ability.Train_Roach, MineralCost: 75, VespeneCost: 25, FoodRequired: 2, BuildTime: 432, SightRange: 9, MovementSpeed: 3.15, Attributes: []api.Attribute{armored, biological}, Weapons: []*api.Weapon{groundWeapon(16, 1, 4, 1.43)}},
+	{UnitId: zerg.Queen, Name: "Queen", Race: api.Race_Zerg, AbilityId: ability.Train_Queen, MineralCost: 150, FoodRequired: 2, BuildTime: 800, SightRange: 9, MovementSpeed: 1.31, Attributes: []api.Attribute{biological}, Weapons: []*api.Weapon{groundWeapon(4, 2, 5, 0.71), airWeapon(9, 1, 7, 0.71)}},
+	{UnitId: zerg.Mutalisk, Name: "Mutalisk", Race: api.Race_Zerg, AbilityId: ability.Train_Mutalisk, MineralCost: 100, VespeneCost: 100, FoodRequired: 2, BuildTime: 528, SightRange: 11, MovementSpeed: 5.6, Attributes: []api.Attribute{light, biological}, Weapons: []*api.Weapon{anyWeapon(9, 1, 3, 1.09)}},
+	{UnitId: zerg.Overlord, Name: "Overlord", Race: api.Race_Zerg, AbilityId: ability.Train_Overlord, MineralCost: 100, FoodProvided: 8, BuildTime: 400, SightRange: 11, MovementSpeed: 0.902, Attributes: []api.Attribute{armored, biological}},
+	{UnitId: zerg.Changeling, Name: "Changeling", Race: api.Race_Zerg, SightRange: 8, MovementSpeed: 3.15, Attributes: []api.Attribute{light, biological}},
+	{UnitId: zerg.Larva, Name: "Larva", Race: api.Race_Zerg, SightRange: 5, MovementSpeed: 0.56, Attributes: []api.Attribute{light, biological}},
+	{UnitId: zerg.Hatchery, Name: "Hatchery", Race: api.Race_Zerg, AbilityId: ability.Build_Hatchery, MineralCost: 350, FoodProvided: 6, BuildTime: 1590, SightRange: 12, Attributes: []api.Attribute{armored, biological, structure}},
+	{UnitId: zerg.SpawningPool, Name: "SpawningPool", Race: api.Race_Zerg, AbilityId: ability.Build_SpawningPool, MineralCost: 250, BuildTime: 1040, SightRange: 9, Attributes: []api.Attribute{armored, biological, structure}},
+	{UnitId: zerg.Extractor, Name: "Extractor", Race: api.Race_Zerg, AbilityId: ability.Build_Extractor, MineralCost: 75, BuildTime: 480, SightRange: 9, Attributes: []api.Attribute{armored, biological, structure}},
+	{UnitId: zerg.SpineCrawler, Name: "SpineCrawler", Race: api.Race_Zerg, AbilityId: ability.Build_SpineCrawler, MineralCost: 150, BuildTime: 800, SightRange: 11, Attributes: []api.Attribute{armored, biological, structure}, Weapons: []*api.Weapon{groundWeapon(25, 1, 7, 1.32)}},
+	{UnitId: zerg.Spire, Name: "Spire", Race: api.Race_Zerg, AbilityId: ability.Build_Spire, MineralCost: 250, VespeneCost: 200, BuildTime: 1600, SightRange: 9, Attributes: []api.Attribute{armored, biological, structure}},
+
+	// Protoss
+	{UnitId: protoss.Probe, Name: "Probe", Race: api.Race_Protoss, AbilityId: ability.Train_Probe, MineralCost: 50, FoodRequired: 1, BuildTime: 272, SightRange: 8, MovementSpeed: 2.8125, Attributes: []api.Attribute{light, mechanical}, Weapons: []*api.Weapon{groundWeapon(5, 1, 0.1, 1.07)}},
+	{UnitId: protoss.Zealot, Name: "Zealot", Race: api.Race_Protoss, AbilityId: ability.Train_Zealot, MineralCost: 100, FoodRequired: 2, BuildTime: 608, SightRange: 9, MovementSpeed: 2.25, Attributes: []api.Attribute{light, biological}, Weapons: []*api.Weapon{groundWeapon(8, 2, 0.1, 0.857)}},
+	{UnitId: protoss.Stalker, Name: "Stalker", Race: api.Race_Protoss, AbilityId: ability.Train_Stalker, MineralCost: 125, VespeneCost: 50, FoodRequired: 2, BuildTime: 672, SightRange: 10, MovementSpeed: 2.95, Attributes: []api.Attribute{armored, mechanical}, Weapons: []*api.Weapon{anyWeapon(13, 1, 6, 1.34)}},
+	{UnitId: protoss.DarkTemplar, Name: "DarkTemplar", Race: api.Race_Protoss, AbilityId: ability.Train_DarkTemplar, MineralCost: 125, VespeneCost: 125, FoodRequired: 2, BuildTime: 880, SightRange: 8, MovementSpeed: 2.81, Attributes: []api.Attribute{light, biological}, Weapons: []*api.Weapon{groundWeapon(45, 1, 0.1, 1.21)}},
+	{UnitId: protoss.VoidRay, Name: "VoidRay", Race: api.Race_Protoss, AbilityId: ability.Train_VoidRay, MineralCost: 250, VespeneCost: 150, FoodRequired: 4, BuildTime: 832, SightRange: 10, MovementSpeed: 3.85, Attributes: []api.Attribute{armored, mechanical}, Weapons: []*api.Weapon{anyWeapon(6, 1, 6, 0.36)}},
+	{UnitId: protoss.Nexus, Name: "Nexus", Race: api.Race_Protoss, AbilityId: ability.Build_Nexus, MineralCost: 400, FoodProvided: 15, BuildTime: 1590, SightRange: 11, Attributes: []api.Attribute{armored, structure}},
+	{UnitId: protoss.Pylon, Name: "Pylon", Race: api.Race_Protoss, AbilityId: ability.Build_Pylon, MineralCost: 100, FoodProvided: 8, BuildTime: 400, SightRange: 9, Attributes: []api.Attribute{armored, structure}},
+	{UnitId: protoss.Gateway, Name: "Gateway", Race: api.Race_Protoss, AbilityId: ability.Build_Gateway, MineralCost: 150, BuildTime: 1040, SightRange: 9, Attributes: []api.Attribute{armored, structure}},
+	{UnitId: protoss.Assimilator, Name: "Assimilator", Race: api.Race_Protoss, AbilityId: ability.Build_Assimilator, MineralCost: 75, BuildTime: 480, SightRange: 9, Attributes: []api.Attribute{armored, structure}},
+	{UnitId: protoss.Forge, Name: "Forge", Race: api.Race_Protoss, AbilityId: ability.Build_Forge, MineralCost: 150, BuildTime: 720, SightRange: 9, Attributes: []api.Attribute{armored, structure}},
+	{UnitId: protoss.PhotonCannon, Name: "PhotonCannon", Race: api.Race_Protoss, AbilityId: ability.Build_PhotonCannon, MineralCost: 150, BuildTime: 640, SightRange: 11, Attributes: []api.Attribute{armored, structure}, Weapons: []*api.Weapon{anyWeapon(20, 1, 7, 1.25)}},
+	{UnitId: protoss.Stargate, Name: "Stargate", Race: api.Race_Protoss, AbilityId: ability.Build_Stargate, MineralCost: 150, VespeneCost: 150, BuildTime: 960, SightRange: 9, Attributes: []api.Attribute{armored, structure}},
+	{UnitId: protoss.DarkShrine, Name: "DarkShrine", Race: api.Race_Protoss, AbilityId: ability.Build_DarkShrine, MineralCost: 150, VespeneCost: 150, BuildTime: 1600, SightRange: 9, Attributes: []api.Attribute{armored, structure}},
+
+	// Neutral
+	{UnitId: neutral.MineralField, Name: "MineralField", HasMinerals: true, SightRange: 1, Attributes: []api.Attribute{structure}},
+	{UnitId: neutral.MineralField750, Name: "MineralField750", HasMinerals: true, SightRange: 1, Attributes: []api.Attribute{structure}},
+	{UnitId: neutral.VespeneGeyser, Name: "VespeneGeyser", HasVespene: true, SightRange: 1, Attributes: []api.Attribute{structure}},
+}
+
+// upgrades is the subset of the game's upgrade data that's needed to run the
+// bot's logic.
+var upgrades = []*api.UpgradeData{
+	{UpgradeId: upgrade.TerranInfantryWeaponsLevel1, Name: "TerranInfantryWeaponsLevel1", AbilityId: ability.Research_TerranInfantryWeaponsLevel1, MineralCost: 100, VespeneCost: 100, ResearchTime: 2560},
+	{UpgradeId: upgrade.TerranInfantryWeaponsLevel2, Name: "TerranInfantryWeaponsLevel2", AbilityId: ability.Research_TerranInfantryWeaponsLevel2, MineralCost: 175, VespeneCost: 175, ResearchTime: 3040},
+	{UpgradeId: upgrade.TerranInfantryWeaponsLevel3, Name: "TerranInfantryWeaponsLevel3", AbilityId: ability.Research_TerranInfantryWeaponsLevel3, MineralCost: 250, VespeneCost: 250, ResearchTime: 3520},
+	{UpgradeId: upgrade.TerranInfantryArmorsLevel1, Name: "TerranInfantryArmorsLevel1", AbilityId: ability.Research_TerranInfantryArmorLevel1, MineralCost: 100, VespeneCost: 100, ResearchTime: 2560},
+	{UpgradeId: upgrade.TerranInfantryArmorsLevel2, Name: "TerranInfantryArmorsLevel2", AbilityId: ability.Research_TerranInfantryArmorLevel2, MineralCost: 175, VespeneCost: 175, ResearchTime: 3040},
+	{UpgradeId: upgrade.TerranInfantryArmorsLevel3, Name: "TerranInfantryArmorsLevel3", AbilityId: ability.Research_TerranInfantryArmorLevel3, MineralCost: 250, VespeneCost: 250, ResearchTime: 3520},
+	{UpgradeId: upgrade.Stimpack, Name: "Stimpack", AbilityId: ability.Research_Stimpack, MineralCost: 100, VespeneCost: 100, ResearchTime: 1600},
+	{UpgradeId: upgrade.ShieldWall, Name: "ShieldWall", AbilityId: ability.Research_CombatShield, MineralCost: 100, VespeneCost: 100, ResearchTime: 1120},
+}
+
+// Data returns the game data used by the simulation. Unit types and upgrades
+// that aren't known to the simulation are present but empty, since s2l
+// indexes them by ID.
+func Data() *api.ResponseData {
+	units := make([]*api.UnitTypeData, maxUnitTypeID)
+	for id := range units {
+		units[id] = &api.UnitTypeData{UnitId: api.UnitTypeID(id)}
+	}
+	for _, u := range unitTypes {
+		unit := *u
+		unit.Available = true
+		units[u.UnitId] = &unit
+	}
+
+	researches := make([]*api.UpgradeData, maxUpgradeID)
+	for id := range researches {
+		researches[id] = &api.UpgradeData{UpgradeId: api.UpgradeID(id)}
+	}
+	for _, u := range upgrades {
+		research := *u
+		researches[u.UpgradeId] = &research
+	}
+
+	return &api.ResponseData{Units: units, Upgrades: researches}
+}
+
+// UnitType returns the data of a unit type known by the simulation.
+func UnitType(id api.UnitTypeID) *api.UnitTypeData {
+	for _, u := range unitTypes {
+		if u.UnitId == id {
+			return u
+		}
+	}
+
+	return &api.UnitTypeData{UnitId: id}
+}
+
+func groundWeapon(damage float32, attacks uint32, weaponRange float32, speed float32) *api.Weapon {
+	return &api.Weapon{Type: api.Weapon_Ground, Damage: damage, Attacks: attacks, Range: weaponRange, Speed: speed}
+}
+
+func airWeapon(damage float32, attacks uint32, weaponRange float32, speed float32) *api.Weapon {
+	return &api.Weapon{Type: api.Weapon_Air, Damage: damage, Attacks: attacks, Range: weaponRange, Speed: speed}
+}
+
+func anyWeapon(damage float32, attacks uint32, weaponRange float32, speed float32) *api.Weapon {
+	return &api.Weapon{Type: api.Weapon_Any, Damage: damage, Attacks: attacks, Range: weaponRange, Speed: speed}
+}
+
+// vitals are the values of a unit type that aren't part of the game data but
+// are part of every unit in observations.
+type vitals struct {
+	health float32
+	shield float32
+	energy float32
+	radius float32
+	cargo  int32
+}
+
+// defaultVitals is used for unit types that aren't in unitVitals.
+var defaultVitals = vitals{health: 100, radius: 0.5}
+
+var unitVitals = map[api.UnitTypeID]vitals{
+	terran.SCV:                {health: 45, radius: 0.375},
+	terran.MULE:               {health: 60, radius: 0.375},
+	terran.Marine:             {health: 45, radius: 0.375},
+	terran.Marauder:           {health: 125, radius: 0.5625},
+	terran.Reaper:             {health: 60, radius: 0.375},
+	terran.Hellion:            {health: 90, radius: 0.625},
+	terran.SiegeTank:          {health: 175, radius: 0.875},
+	terran.SiegeTankSieged:    {health: 175, radius: 0.875},
+	terran.Medivac:            {health: 150, energy: 50, radius: 0.75, cargo: 8},
+	terran.VikingFighter:      {health: 135, radius: 0.75},
+	terran.CommandCenter:      {health: 1500, radius: 2.75},
+	terran.OrbitalCommand:     {health: 1500, energy: 50, radius: 2.75},
+	terran.PlanetaryFortress:  {health: 1500, radius: 2.75},
+	terran.SupplyDepot:        {health: 400, radius: 1.375},
+	terran.SupplyDepotLowered: {health: 400, radius: 1.375},
+	terran.Refinery:           {health: 500, radius: 1.75},
+	terran.Barracks:           {health: 1000, radius: 1.8125},
+	terran.Factory:            {health: 1250, radius: 1.8125},
+	terran.Starport:           {health: 1300, radius: 1.8125},
+	terran.EngineeringBay:     {health: 850, radius: 1.8125},
+	terran.Armory:             {health: 750, radius: 1.8125},
+	terran.MissileTurret:      {health: 250, radius: 1.125},
+	terran.BarracksTechLab:    {health: 400, radius: 1},
+	terran.BarracksReactor:    {health: 400, radius: 1},
+	terran.FactoryTechLab:     {health: 400, radius: 1},
+	terran.FactoryReactor:     {health: 400, radius: 1},
+	terran.StarportTechLab:    {health: 400, radius: 1},
+	terran.StarportReactor:    {health: 400, radius: 1},
+
+	zerg.Drone:        {health: 40, radius: 0.375},
+	zerg.Zergling:     {health: 35, radius: 0.375},
+	zerg.Baneling:     {health: 30, radius: 0.375},
+	zerg.Roach:        {health: 145, radius: 0.625},
+	zerg.Queen:        {health: 175, energy: 25, radius: 0.875},
+	zerg.Mutalisk:     {health: 120, radius: 0.5},
+	zerg.Overlord:     {health: 200, radius: 1},
+	zerg.Changeling:   {health: 5, radius: 0.375},
+	zerg.Larva:        {health: 25, radius: 0.25},
+	zerg.Hatchery:     {health: 1500, radius: 2.75},
+	zerg.SpawningPool: {health: 1000, radius: 1.8125},
+	zerg.Extractor:    {health: 500, radius: 1.75},
+	zerg.SpineCrawler: {health: 300, radius: 1.125},
+	zerg.Spire:        {health: 850, radius: 1.375},
+
+	protoss.Probe:        {health: 20, shield: 20, radius: 0.375},
+	protoss.Zealot:       {health: 100, shield: 50, radius: 0.5},
+	protoss.Stalker:      {health: 80, shield: 80, radius: 0.625},
+	protoss.DarkTemplar:  {health: 40, shield: 80, radius: 0.375},
+	protoss.VoidRay:      {health: 150, shield: 100, radius: 1},
+	protoss.Nexus:        {health: 1000, shield: 1000, energy: 50, radius: 2.75},
+	protoss.Pylon:        {health: 200, shield: 200, radius: 1.125},
+	protoss.Gateway:      {health: 500, shield: 500, radius: 1.8125},
+	protoss.Assimilator:  {health: 300, shield: 300, radius: 1.75},
+	protoss.Forge:        {health: 400, shield: 400, radius: 1.8125},
+	protoss.PhotonCannon: {health: 150, shield: 150, radius: 1.125},
+	protoss.Stargate:     {health: 600, shield: 600, radius: 1.8125},
+	protoss.DarkShrine:   {health: 500, shield: 500, radius: 1.375},
+
+	neutral.MineralField:    {radius: 1.125},
+	neutral.MineralField750: {radius: 1.125},
+	neutral.VespeneGeyser:   {radius: 1.8125},
+}
+
+// unitVitalsOf returns the vitals of a unit type.
+func unitVitalsOf(id api.UnitTypeID) vitals {
+	if v, ok := unitVitals[id]; ok {
+		return v
+	}
+
+	return defaultVitals
+}
+
+// unitAbilities are the abilities that units can use outside of the ones
+// unlocked by structures and upgrades.
+var unitAbilities = map[api.UnitTypeID][]api.AbilityID{
+	terran.Medivac: {ability.Effect_Heal, ability.Effect_MedivacIgniteAfterburners, ability.Load_Medivac, ability.UnloadAllAt_Medivac},
+}
+
+// availableAbilities lists the abilities a unit can use right now. Afterburners
+// are on cooldown while they're active.
+func availableAbilities(unit *api.Unit) []*api.AvailableAbility {
+	if unit == nil {
+		return nil
+	}
+
+	var available []*api.AvailableAbility
+	for _, id := range unitAbilities[unit.UnitType] {
+		if id == ability.Effect_MedivacIgniteAfterburners && slices.Contains(unit.BuffIds, buff.MedivacSpeedBoost) {
+			continue
+		}
+
+		available = append(available, &api.AvailableAbility{AbilityId: id})
+	}
+
+	return available
+}
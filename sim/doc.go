@@ -0,0 +1,3 @@
+// sim contains a headless simulation harness that runs the bot against a fake
+// StarCraft II API server fed with scripted observations.
+package sim
@@ -0,0 +1,163 @@
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+func TestRun_DestroyedStructuresAreForgotten(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	hatchery := s.Add(api.Alliance_Enemy, zerg.Hatchery, s.EnemyStart())
+	pool := s.Add(api.Alliance_Enemy, zerg.SpawningPool, s.EnemyStart()-point.Pt(6, 0))
+	frames := s.Frames(2)
+
+	// The spawning pool is destroyed while its position is visible
+	s.Remove(pool.Tag)
+	frames = append(frames, s.Frames(2)...)
+
+	result, err := sim.Run(s.Info, frames, &bot.Strategy{Name: "Nothing"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	memory := result.Bot.State.EnemyMemory
+	if enemy := memory[hatchery.Tag]; enemy == nil || enemy.UnitType != zerg.Hatchery || enemy.FirstSeen != 0 {
+		t.Errorf("EnemyMemory[hatchery] = %v, expected a hatchery first seen at %d", enemy, 0)
+	}
+
+	if enemy := memory[pool.Tag]; enemy != nil {
+		t.Errorf("EnemyMemory[pool] = %v, expected %v", enemy, nil)
+	}
+}
+
+func TestRun_EnemiesAreRememberedInFog(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	zergling := s.Add(api.Alliance_Enemy, zerg.Zergling, s.EnemyNatural())
+	frames := s.Frames(2)
+	lastSeen := int(s.Loop) - sim.LoopsPerStep
+
+	s.Remove(zergling.Tag)
+	s.Fog = true
+	frames = append(frames, s.Frames(2)...)
+
+	result, err := sim.Run(s.Info, frames, &bot.Strategy{Name: "Nothing"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	enemy := result.Bot.State.EnemyMemory[zergling.Tag]
+	if enemy == nil {
+		t.Fatalf("EnemyMemory[zergling] = %v, expected a zergling", enemy)
+	}
+
+	if enemy.LastSeen != lastSeen {
+		t.Errorf("LastSeen = %d, expected %d", enemy.LastSeen, lastSeen)
+	}
+
+	if enemy.Point() != s.EnemyNatural() {
+		t.Errorf("Point() = %v, expected %v", enemy.Point(), s.EnemyNatural())
+	}
+}
+
+func TestRun_DeadEnemiesAreForgotten(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	zergling := s.Add(api.Alliance_Enemy, zerg.Zergling, s.EnemyNatural())
+	frames := s.Frames(2)
+
+	s.Remove(zergling.Tag)
+	s.Fog = true
+	dead := s.Frames(2)
+	dead[0].Observation.RawData.Event = &api.Event{DeadUnits: []api.UnitTag{zergling.Tag}}
+	frames = append(frames, dead...)
+
+	result, err := sim.Run(s.Info, frames, &bot.Strategy{Name: "Nothing"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if enemy := result.Bot.State.EnemyMemory[zergling.Tag]; enemy != nil {
+		t.Errorf("EnemyMemory[zergling] = %v, expected %v", enemy, nil)
+	}
+}
+
+func TestRun_EnemyUnitsExpire(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	hatchery := s.Add(api.Alliance_Enemy, zerg.Hatchery, s.EnemyStart())
+	zergling := s.Add(api.Alliance_Enemy, zerg.Zergling, s.EnemyNatural())
+	frames := s.Frames(2)
+
+	// A minute later, without vision
+	s.Remove(zergling.Tag)
+	s.Loop += 60 * 22
+	s.Fog = true
+	frames = append(frames, s.Frames(2)...)
+
+	result, err := sim.Run(s.Info, frames, &bot.Strategy{Name: "Nothing"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	memory := result.Bot.State.EnemyMemory
+	if enemy := memory[zergling.Tag]; enemy != nil {
+		t.Errorf("EnemyMemory[zergling] = %v, expected %v", enemy, nil)
+	}
+
+	if enemy := memory[hatchery.Tag]; enemy == nil {
+		t.Errorf("EnemyMemory[hatchery] = %v, expected a hatchery", enemy)
+	}
+}
+
+func TestRun_AttackWaveTargetsRememberedBuildings(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	for i := 0; i < 6; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, s.EnemyNatural()+point.Pt(2, float64(i)))
+	}
+	hatchery := s.Add(api.Alliance_Enemy, zerg.Hatchery, s.EnemyStart())
+	frames := s.Frames(1)
+
+	s.Remove(hatchery.Tag)
+	s.Fog = true
+	frames = append(frames, s.Frames(3)...)
+
+	strategy := &bot.Strategy{
+		Name: "Attack",
+		Steps: bot.BuildOrder{{
+			Name: "Attack Wave",
+			Predicate: func(b *bot.Bot) bool {
+				// The wave is created after the hatchery went back in the fog
+				return len(b.State.AttackWaves) == 0 && b.Loop > 0
+			},
+			Execute: func(b *bot.Bot) {
+				marines := b.Units.My.OfType(terran.Marine)
+				wave := bot.AttackWave{Tags: marines.Tags(), Target: marines.Center()}
+				b.State.AttackWaves = append(b.State.AttackWaves, wave)
+			},
+			Next: func(b *bot.Bot) bool {
+				return true
+			},
+		}},
+	}
+
+	result, err := sim.Run(s.Info, frames, strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	targeted := false
+	for _, command := range result.CommandsWith(ability.Attack) {
+		if point.Pt2(command.GetTargetWorldSpacePos()) == point.Pt3(hatchery.Pos) {
+			targeted = true
+		}
+	}
+
+	if !targeted {
+		t.Errorf("targeted = %v, expected %v", targeted, true)
+	}
+}
@@ -0,0 +1,43 @@
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+func TestRun_GameRecordsResult(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	frames := s.Frames(3)
+
+	last := frames[len(frames)-1]
+	last.PlayerResult = []*api.PlayerResult{
+		{PlayerId: 1, Result: api.Result_Victory},
+		{PlayerId: 2, Result: api.Result_Defeat},
+	}
+	last.Observation.Score = &api.Score{ScoreDetails: &api.ScoreDetails{KilledValueUnits: 350}}
+
+	result, err := sim.Run(s.Info, frames, &macro.Standard)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	game := result.Game
+	if game.Result != api.Result_Victory {
+		t.Errorf("Result = %v, expected %v", game.Result, api.Result_Victory)
+	}
+
+	if game.PeakSupply != 12 {
+		t.Errorf("PeakSupply = %d, expected %d", game.PeakSupply, 12)
+	}
+
+	if game.Loops != int(last.Observation.GameLoop) {
+		t.Errorf("Loops = %d, expected %d", game.Loops, last.Observation.GameLoop)
+	}
+
+	if killed := game.Score.GetScoreDetails().GetKilledValueUnits(); killed != 350 {
+		t.Errorf("KilledValueUnits = %v, expected %v", killed, 350)
+	}
+}
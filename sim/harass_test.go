@@ -0,0 +1,244 @@
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+func TestRun_HarassStepFormsDrop(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	rally := s.MyStart() + point.Pt(8, 8)
+	medivac := s.Add(api.Alliance_Self, terran.Medivac, rally)
+	for i := 0; i < 10; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, rally+point.Pt(float64(i%5), float64(i/5)))
+	}
+
+	file := macro.StrategyFile{Name: "Harass", Steps: []macro.StepFile{{Kind: "harass"}}}
+	strategy, err := file.Strategy()
+	if err != nil {
+		t.Fatalf("Strategy() error = %v", err)
+	}
+
+	result, err := sim.Run(s.Info, s.Frames(2), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	squads := result.Bot.State.HarassSquads
+	if len(squads) != 1 {
+		t.Fatalf("len(HarassSquads) = %d, expected %d", len(squads), 1)
+	}
+
+	if got := squads[0].Tags.Len(); got != 9 {
+		t.Errorf("len(Tags) = %d, expected %d", got, 9)
+	}
+
+	boarding := map[api.UnitTag]bool{}
+	for _, command := range result.CommandsWith(ability.Smart) {
+		if command.GetTargetUnitTag() != medivac.Tag {
+			continue
+		}
+		for _, tag := range command.UnitTags {
+			boarding[tag] = true
+		}
+	}
+
+	if len(boarding) != 8 {
+		t.Errorf("len(boarding) = %d, expected %d", len(boarding), 8)
+	}
+}
+
+func TestRun_HarassSquadAvoidsEnemyArmy(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	for i := 0; i < 4; i++ {
+		s.Add(api.Alliance_Self, terran.Hellion, s.MyStart()+point.Pt(6, float64(i)))
+	}
+	army := point.Pt(sim.MapSize-10, 10)
+	for i := 0; i < 4; i++ {
+		s.Add(api.Alliance_Enemy, zerg.Roach, army+point.Pt(float64(i), 0))
+	}
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		army := b.FindArmy()
+		squad := bot.HarassSquad{Tags: army.Tags(), Target: s.EnemyStart(), State: bot.Loading}
+		b.State.HarassSquads = append(b.State.HarassSquads, squad)
+	})
+
+	result, err := sim.Run(s.Info, s.Frames(2), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	squads := result.Bot.State.HarassSquads
+	if len(squads) != 1 {
+		t.Fatalf("len(HarassSquads) = %d, expected %d", len(squads), 1)
+	}
+
+	if squads[0].State != bot.Travelling {
+		t.Errorf("State = %v, expected %v", squads[0].State, bot.Travelling)
+	}
+
+	for _, waypoint := range squads[0].Path {
+		if dist := waypoint.Dist(army); dist < 20 {
+			t.Errorf("waypoint %v is %v away from the enemy army, expected at least %v", waypoint, dist, 20)
+		}
+	}
+}
+
+func TestRun_HarassSquadFocusesWorkers(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	target := s.EnemyNatural()
+	s.Add(api.Alliance_Enemy, zerg.Hatchery, target)
+	for i := 0; i < 4; i++ {
+		s.Add(api.Alliance_Self, terran.Hellion, target+point.Pt(0, float64(i)))
+	}
+	s.Add(api.Alliance_Enemy, zerg.Drone, target+point.Pt(3, 0))
+	weak := s.Add(api.Alliance_Enemy, zerg.Drone, target+point.Pt(3, 1))
+	weak.Health = 10
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		army := b.FindArmy()
+		squad := bot.HarassSquad{Tags: army.Tags(), Target: target, State: bot.Harassing, Path: point.Points{target}, Waypoint: 1}
+		b.State.HarassSquads = append(b.State.HarassSquads, squad)
+	})
+
+	result, err := sim.Run(s.Info, s.Frames(2), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	commands := result.CommandsWith(ability.Attack)
+	if len(commands) == 0 {
+		t.Fatalf("len(CommandsWith(Attack)) = 0, expected more than 0")
+	}
+
+	for _, command := range commands {
+		if got := command.GetTargetUnitTag(); got != weak.Tag {
+			t.Errorf("Attack target = %v, expected %v", got, weak.Tag)
+		}
+	}
+}
+
+func TestRun_HarassSquadLeavesWhenDefended(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	target := s.EnemyNatural()
+	s.Add(api.Alliance_Enemy, zerg.Hatchery, target)
+	for i := 0; i < 4; i++ {
+		s.Add(api.Alliance_Self, terran.Hellion, target+point.Pt(0, float64(i)))
+	}
+	s.Add(api.Alliance_Enemy, zerg.Drone, target+point.Pt(3, 0))
+	s.Add(api.Alliance_Enemy, zerg.Queen, target+point.Pt(5, 0))
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		army := b.FindArmy()
+		squad := bot.HarassSquad{Tags: army.Tags(), Target: target, State: bot.Harassing, Path: point.Points{target}, Waypoint: 1}
+		b.State.HarassSquads = append(b.State.HarassSquads, squad)
+	})
+
+	result, err := sim.Run(s.Info, s.Frames(2), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	squads := result.Bot.State.HarassSquads
+	if len(squads) != 1 {
+		t.Fatalf("len(HarassSquads) = %d, expected %d", len(squads), 1)
+	}
+
+	if squads[0].State != bot.Leaving {
+		t.Errorf("State = %v, expected %v", squads[0].State, bot.Leaving)
+	}
+
+	if commands := result.CommandsWith(ability.Move); len(commands) == 0 {
+		t.Errorf("len(CommandsWith(Move)) = 0, expected more than 0")
+	}
+}
+
+func TestRun_HarassSquadCountsResults(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	target := s.EnemyNatural()
+	s.Add(api.Alliance_Enemy, zerg.Hatchery, target)
+	var hellions []*api.Unit
+	for i := 0; i < 4; i++ {
+		hellions = append(hellions, s.Add(api.Alliance_Self, terran.Hellion, target+point.Pt(0, float64(i))))
+	}
+	drone := s.Add(api.Alliance_Enemy, zerg.Drone, target+point.Pt(3, 0))
+	s.Add(api.Alliance_Enemy, zerg.Drone, target+point.Pt(3, 1))
+	frames := s.Frames(2)
+
+	s.Remove(drone.Tag)
+	s.Remove(hellions[0].Tag)
+	dead := s.Frames(1)
+	dead[0].Observation.RawData.Event = &api.Event{DeadUnits: []api.UnitTag{drone.Tag, hellions[0].Tag}}
+	frames = append(frames, dead...)
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		army := b.FindArmy()
+		squad := bot.HarassSquad{Tags: army.Tags(), Target: target, State: bot.Harassing, Path: point.Points{target}, Waypoint: 1}
+		b.State.HarassSquads = append(b.State.HarassSquads, squad)
+	})
+
+	result, err := sim.Run(s.Info, frames, strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	squads := result.Bot.State.HarassSquads
+	if len(squads) != 1 {
+		t.Fatalf("len(HarassSquads) = %d, expected %d", len(squads), 1)
+	}
+
+	if got := squads[0].WorkersKilled; got != 1 {
+		t.Errorf("WorkersKilled = %d, expected %d", got, 1)
+	}
+
+	if got := squads[0].UnitsLost; got != 1 {
+		t.Errorf("UnitsLost = %d, expected %d", got, 1)
+	}
+}
+
+func TestRun_HarassSquadIgnoresWorkersAwayFromTarget(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	target := s.EnemyNatural()
+	s.Add(api.Alliance_Enemy, zerg.Hatchery, target)
+	s.Add(api.Alliance_Enemy, zerg.Drone, target+point.Pt(3, 0))
+	away := target + point.Pt(0, 20)
+	for i := 0; i < 4; i++ {
+		s.Add(api.Alliance_Self, terran.Hellion, away+point.Pt(0, float64(i)))
+	}
+	drone := s.Add(api.Alliance_Enemy, zerg.Drone, away+point.Pt(3, 0))
+	frames := s.Frames(2)
+
+	s.Remove(drone.Tag)
+	dead := s.Frames(1)
+	dead[0].Observation.RawData.Event = &api.Event{DeadUnits: []api.UnitTag{drone.Tag}}
+	frames = append(frames, dead...)
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		army := b.FindArmy()
+		squad := bot.HarassSquad{Tags: army.Tags(), Target: target, State: bot.Harassing, Path: point.Points{target}, Waypoint: 1}
+		b.State.HarassSquads = append(b.State.HarassSquads, squad)
+	})
+
+	result, err := sim.Run(s.Info, frames, strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	squads := result.Bot.State.HarassSquads
+	if len(squads) != 1 {
+		t.Fatalf("len(HarassSquads) = %d, expected %d", len(squads), 1)
+	}
+
+	if got := squads[0].WorkersKilled; got != 0 {
+		t.Errorf("WorkersKilled = %d, expected %d", got, 0)
+	}
+}
@@ -0,0 +1,111 @@
+package sim
+
+import (
+	"fmt"
+
+	"github.com/NatoBoram/BlackCompany/agent"
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/record"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+// Result is what happened during a simulation.
+type Result struct {
+	// Bot is the bot that played the simulation.
+	Bot *bot.Bot
+
+	// Server is the fake game the bot played in.
+	Server *Server
+
+	// Game is what happened during the simulation.
+	Game *agent.Game
+}
+
+// Run plays a strategy through the provided observations and returns what the
+// bot did.
+func Run(info *api.ResponseGameInfo, frames []*api.ResponseObservation, strategy *bot.Strategy) (*Result, error) {
+	return run(NewServer(info, Data(), frames), agent.Fixed(strategy))
+}
+
+// Replay plays a recorded game with the chosen strategy and returns what the
+// bot did.
+func Replay(recording *record.Recording, strategist agent.Strategist) (*Result, error) {
+	return run(NewServer(recording.Info, recording.Data, recording.Frames), strategist)
+}
+
+func run(server *Server, strategist agent.Strategist) (*Result, error) {
+	defer server.Close()
+
+	c, err := server.Connect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the simulation: %w", err)
+	}
+
+	b := bot.New(c)
+	game := agent.Run(b, strategist, nil)
+
+	return &Result{Bot: b, Server: server, Game: game}, nil
+}
+
+// Setup is a strategy that calls a function once, on its first step, to put
+// the bot in the state that a test needs. Each simulation needs its own setup.
+func Setup(setup func(b *bot.Bot)) *bot.Strategy {
+	done := false
+	return &bot.Strategy{
+		Name: "Setup",
+		Steps: bot.BuildOrder{{
+			Name: "Setup",
+			Predicate: func(b *bot.Bot) bool {
+				return !done
+			},
+			Execute: func(b *bot.Bot) {
+				setup(b)
+				done = true
+			},
+			Next: func(b *bot.Bot) bool {
+				return true
+			},
+		}},
+	}
+}
+
+// Actions returns every action that the bot sent during the simulation.
+func (r *Result) Actions() []*api.Action {
+	return r.Server.Actions()
+}
+
+// Commands returns the unit commands that the bot sent during the simulation.
+func (r *Result) Commands() []*api.ActionRawUnitCommand {
+	var commands []*api.ActionRawUnitCommand
+	for _, action := range r.Actions() {
+		if command := action.GetActionRaw().GetUnitCommand(); command != nil {
+			commands = append(commands, command)
+		}
+	}
+
+	return commands
+}
+
+// CommandsWith returns the unit commands that used the provided ability.
+func (r *Result) CommandsWith(ability api.AbilityID) []*api.ActionRawUnitCommand {
+	var commands []*api.ActionRawUnitCommand
+	for _, command := range r.Commands() {
+		if command.AbilityId == ability {
+			commands = append(commands, command)
+		}
+	}
+
+	return commands
+}
+
+// Chat returns the chat messages that the bot sent during the simulation.
+func (r *Result) Chat() []string {
+	var messages []string
+	for _, action := range r.Actions() {
+		if chat := action.GetActionChat(); chat != nil {
+			messages = append(messages, chat.Message)
+		}
+	}
+
+	return messages
+}
@@ -0,0 +1,115 @@
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/neutral"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+func TestRun_StandardTrainsSCVs(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+
+	result, err := sim.Run(s.Info, s.Frames(4), &macro.Standard)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	commands := result.CommandsWith(ability.Train_SCV)
+	if len(commands) == 0 {
+		t.Errorf("len(CommandsWith(Train_SCV)) = %d, expected more than 0", len(commands))
+	}
+}
+
+func TestRun_StandardBuildsSupplyDepot(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	s.Minerals = 150
+	for i := 0; i < 2; i++ {
+		s.Add(api.Alliance_Self, terran.SCV, s.MyStart()-point.Pt(4, 4))
+	}
+
+	result, err := sim.Run(s.Info, s.Frames(4), &macro.Standard)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	commands := result.CommandsWith(ability.Build_SupplyDepot)
+	if len(commands) == 0 {
+		t.Errorf("len(CommandsWith(Build_SupplyDepot)) = %d, expected more than 0", len(commands))
+	}
+}
+
+func TestRun_MiningSendsWorkersToMinerals(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+
+	result, err := sim.Run(s.Info, s.Frames(2), &macro.Standard)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	minerals := map[api.UnitTag]bool{}
+	for _, mineral := range s.OfType(api.Alliance_Neutral, neutral.MineralField, neutral.MineralField750) {
+		minerals[mineral.Tag] = true
+	}
+
+	workers := map[api.UnitTag]bool{}
+	for _, command := range result.CommandsWith(ability.Smart) {
+		if !minerals[command.GetTargetUnitTag()] {
+			continue
+		}
+
+		for _, tag := range command.UnitTags {
+			workers[tag] = true
+		}
+	}
+
+	if scvs := s.OfType(api.Alliance_Self, terran.SCV); len(workers) != len(scvs) {
+		t.Errorf("len(workers) = %d, expected %d", len(workers), len(scvs))
+	}
+}
+
+func TestRun_DefenseWaveAttacksEnemiesInBase(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	marines := make(map[api.UnitTag]bool)
+	for i := 0; i < 4; i++ {
+		marine := s.Add(api.Alliance_Self, terran.Marine, s.MyStart()+point.Pt(4, float64(i)))
+		marines[marine.Tag] = true
+	}
+
+	enemies := s.MyStart() + point.Pt(6, 6)
+	for i := 0; i < 3; i++ {
+		s.Add(api.Alliance_Enemy, zerg.Zergling, enemies+point.Pt(float64(i)*0.5, 0))
+	}
+
+	result, err := sim.Run(s.Info, s.Frames(3), &macro.Standard)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	attacking := map[api.UnitTag]bool{}
+	for _, command := range result.CommandsWith(ability.Attack) {
+		// Marines focus the zerglings that are already in range
+		target := point.Pt2(command.GetTargetWorldSpacePos())
+		if tag := command.GetTargetUnitTag(); tag != 0 {
+			target = point.Pt3(s.Unit(tag).Pos)
+		}
+
+		if target.Dist(enemies) > 4 {
+			t.Errorf("Attack target = %v, expected close to %v", target, enemies)
+		}
+
+		for _, tag := range command.UnitTags {
+			attacking[tag] = true
+		}
+	}
+
+	if len(attacking) != len(marines) {
+		t.Errorf("len(attacking) = %d, expected %d", len(attacking), len(marines))
+	}
+}
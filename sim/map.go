@@ -0,0 +1,135 @@
+package sim
+
+import (
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+// MapSize is the width and height of the simulated map.
+const MapSize = 96
+
+// mapMargin is the unplayable border around the simulated map.
+const mapMargin = 8
+
+// mapHeight is the terrain height of the whole simulated map.
+const mapHeight = 10
+
+// gameInfo creates the game info of a flat map where the bot plays as Terran
+// against the provided race. Cells that are covered by the resources are
+// removed from the pathing grid.
+func gameInfo(enemy api.Race, enemyStart point.Point, resources []*api.Unit) *api.ResponseGameInfo {
+	pathing := newBitImage()
+	placement := newBitImage()
+	height := newByteImage(byte(127 + mapHeight*8))
+
+	for y := mapMargin; y < MapSize-mapMargin; y++ {
+		for x := mapMargin; x < MapSize-mapMargin; x++ {
+			setBit(pathing, x, y, true)
+			setBit(placement, x, y, true)
+		}
+	}
+
+	for _, resource := range resources {
+		for _, cell := range footprint(resource) {
+			setBit(pathing, int(cell.X()), int(cell.Y()), false)
+		}
+	}
+
+	return &api.ResponseGameInfo{
+		MapName:      "Simulation",
+		LocalMapPath: "Simulation.SC2Map",
+		PlayerInfo: []*api.PlayerInfo{{
+			PlayerId:      1,
+			Type:          api.PlayerType_Participant,
+			RaceRequested: api.Race_Terran,
+			RaceActual:    api.Race_Terran,
+			PlayerName:    "BlackCompany",
+		}, {
+			PlayerId:      2,
+			Type:          api.PlayerType_Computer,
+			RaceRequested: enemy,
+			Difficulty:    api.Difficulty_Hard,
+			AiBuild:       api.AIBuild_RandomBuild,
+		}},
+		StartRaw: &api.StartRaw{
+			MapSize:       &api.Size2DI{X: MapSize, Y: MapSize},
+			PathingGrid:   pathing,
+			TerrainHeight: height,
+			PlacementGrid: placement,
+			PlayableArea: &api.RectangleI{
+				P0: &api.PointI{X: mapMargin, Y: mapMargin},
+				P1: &api.PointI{X: MapSize - mapMargin, Y: MapSize - mapMargin},
+			},
+			StartLocations: []*api.Point2D{enemyStart.To2D()},
+		},
+		Options: &api.InterfaceOptions{Raw: true, Score: true},
+	}
+}
+
+// mapState creates a map state where everything is visible, or covered by the
+// fog of war, and there's no creep.
+func mapState(fog bool) *api.MapState {
+	visibility := byte(2)
+	if fog {
+		visibility = 1
+	}
+
+	return &api.MapState{
+		Visibility: newByteImage(visibility),
+		Creep:      newBitImage(),
+	}
+}
+
+// footprint returns the cells covered by a resource.
+func footprint(resource *api.Unit) point.Points {
+	pos := point.Pt3(resource.Pos)
+
+	width, height := 3, 3
+	if resource.MineralContents > 0 {
+		width, height = 2, 1
+	}
+
+	corner := pos - point.Pt(float64(width)/2, float64(height)/2)
+	cells := make(point.Points, 0, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			cells.Add(corner + point.Pt(float64(x), float64(y)))
+		}
+	}
+
+	return cells
+}
+
+// newBitImage creates an image of the map's size with one bit per pixel.
+func newBitImage() *api.ImageData {
+	return &api.ImageData{
+		BitsPerPixel: 1,
+		Size_:        &api.Size2DI{X: MapSize, Y: MapSize},
+		Data:         make([]byte, MapSize*MapSize/8),
+	}
+}
+
+// newByteImage creates an image of the map's size with one byte per pixel.
+func newByteImage(value byte) *api.ImageData {
+	data := make([]byte, MapSize*MapSize)
+	for i := range data {
+		data[i] = value
+	}
+
+	return &api.ImageData{
+		BitsPerPixel: 8,
+		Size_:        &api.Size2DI{X: MapSize, Y: MapSize},
+		Data:         data,
+	}
+}
+
+// setBit sets a pixel of an image with one bit per pixel.
+func setBit(image *api.ImageData, x int, y int, value bool) {
+	index := x + y*int(image.Size_.X)
+	offset := 7 - index%8
+	if value {
+		image.Data[index/8] |= 1 << offset
+	} else {
+		image.Data[index/8] &^= 1 << offset
+	}
+}
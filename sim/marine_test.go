@@ -0,0 +1,207 @@
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/upgrade"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+func TestRun_MarinesFocusFireLowestHealth(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	middle := (s.MyStart() + s.EnemyStart()) / 2
+	for i := 0; i < 3; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, middle+point.Pt(0, float64(i)*3))
+	}
+	s.Add(api.Alliance_Enemy, zerg.Zergling, middle+point.Pt(3, 2))
+	damaged := s.Add(api.Alliance_Enemy, zerg.Zergling, middle+point.Pt(3, 3))
+	damaged.Health = 10
+
+	result, err := sim.Run(s.Info, s.Frames(2), &bot.Strategy{Name: "Nothing"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	commands := result.CommandsWith(ability.Attack)
+	if len(commands) == 0 {
+		t.Fatalf("len(CommandsWith(Attack)) = 0, expected more than 0")
+	}
+
+	for _, command := range commands {
+		if got := command.GetTargetUnitTag(); got != damaged.Tag {
+			t.Errorf("Attack target = %v, expected %v", got, damaged.Tag)
+		}
+	}
+}
+
+func TestRun_MarinesPrioritiseBanelings(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	middle := (s.MyStart() + s.EnemyStart()) / 2
+	s.Add(api.Alliance_Self, terran.Marine, middle)
+	damaged := s.Add(api.Alliance_Enemy, zerg.Zergling, middle+point.Pt(3, 0))
+	damaged.Health = 10
+	baneling := s.Add(api.Alliance_Enemy, zerg.Baneling, middle+point.Pt(4, 0))
+
+	result, err := sim.Run(s.Info, s.Frames(2), &bot.Strategy{Name: "Nothing"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	commands := result.CommandsWith(ability.Attack)
+	if len(commands) == 0 {
+		t.Fatalf("len(CommandsWith(Attack)) = 0, expected more than 0")
+	}
+
+	for _, command := range commands {
+		if got := command.GetTargetUnitTag(); got != baneling.Tag {
+			t.Errorf("Attack target = %v, expected %v", got, baneling.Tag)
+		}
+	}
+}
+
+func TestRun_MarinesStimWhenEngaging(t *testing.T) {
+	tests := []struct {
+		name     string
+		upgrades []api.UpgradeID
+		health   float32
+		medivac  bool
+		stim     bool
+	}{
+		{"healthy", []api.UpgradeID{upgrade.Stimpack}, 45, false, true},
+		{"not researched", nil, 45, false, false},
+		{"hurt", []api.UpgradeID{upgrade.Stimpack}, 30, false, false},
+		{"hurt with medivac", []api.UpgradeID{upgrade.Stimpack}, 30, true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := sim.NewScenario(api.Race_Zerg)
+			s.Upgrades = test.upgrades
+			middle := (s.MyStart() + s.EnemyStart()) / 2
+			marine := s.Add(api.Alliance_Self, terran.Marine, middle)
+			marine.Health = test.health
+			if test.medivac {
+				s.Add(api.Alliance_Self, terran.Medivac, middle+point.Pt(-1, 0))
+			}
+			s.Add(api.Alliance_Enemy, zerg.Roach, middle+point.Pt(4, 0))
+
+			result, err := sim.Run(s.Info, s.Frames(2), &bot.Strategy{Name: "Nothing"})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			if got := len(result.CommandsWith(ability.Effect_Stim_Marine)) > 0; got != test.stim {
+				t.Errorf("stimmed = %v, expected %v", got, test.stim)
+			}
+		})
+	}
+}
+
+func TestRun_MarinesSplitAgainstBanelings(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	middle := (s.MyStart() + s.EnemyStart()) / 2
+	for i := 0; i < 4; i++ {
+		// Marines split between their shots
+		marine := s.Add(api.Alliance_Self, terran.Marine, middle+point.Pt(float64(i%2)*0.5, float64(i/2)*0.5))
+		marine.WeaponCooldown = 10
+	}
+	s.Add(api.Alliance_Enemy, zerg.Baneling, middle+point.Pt(5, 0))
+
+	result, err := sim.Run(s.Info, s.Frames(2), &bot.Strategy{Name: "Nothing"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	commands := result.CommandsWith(ability.Move)
+	if len(commands) < 4 {
+		t.Fatalf("len(CommandsWith(Move)) = %d, expected at least %d", len(commands), 4)
+	}
+
+	for _, command := range commands {
+		target := point.Pt2(command.GetTargetWorldSpacePos())
+		if target.Dist(middle+point.Pt(0.25, 0.25)) < 1 {
+			t.Errorf("Move target = %v, expected away from the clump", target)
+		}
+	}
+}
+
+func TestRun_MarinesShootBanelingsBeforeSplitting(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	middle := (s.MyStart() + s.EnemyStart()) / 2
+	for i := 0; i < 4; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, middle+point.Pt(float64(i%2)*0.5, float64(i/2)*0.5))
+	}
+	baneling := s.Add(api.Alliance_Enemy, zerg.Baneling, middle+point.Pt(4, 0))
+
+	result, err := sim.Run(s.Info, s.Frames(2), &bot.Strategy{Name: "Nothing"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	shooters := map[api.UnitTag]bool{}
+	for _, command := range result.CommandsWith(ability.Attack) {
+		if command.GetTargetUnitTag() != baneling.Tag {
+			continue
+		}
+
+		for _, tag := range command.UnitTags {
+			shooters[tag] = true
+		}
+	}
+
+	if len(shooters) != 4 {
+		t.Errorf("len(shooters) = %d, expected %d", len(shooters), 4)
+	}
+}
+
+func TestRun_MarinesKiteShorterRangedEnemies(t *testing.T) {
+	tests := []struct {
+		name     string
+		enemy    api.UnitTypeID
+		distance float64
+		cooldown float32
+		kite     bool
+	}{
+		{"zergling while cooling down", zerg.Zergling, 1, 10, true},
+		{"zergling when ready", zerg.Zergling, 1, 0, false},
+		{"roach while cooling down", zerg.Roach, 4, 10, true},
+		{"sieged tank while cooling down", terran.SiegeTankSieged, 4, 10, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := sim.NewScenario(api.Race_Zerg)
+			middle := (s.MyStart() + s.EnemyStart()) / 2
+			marine := s.Add(api.Alliance_Self, terran.Marine, middle)
+			marine.WeaponCooldown = test.cooldown
+			enemy := s.Add(api.Alliance_Enemy, test.enemy, middle+point.Pt(test.distance, 0))
+
+			result, err := sim.Run(s.Info, s.Frames(2), &bot.Strategy{Name: "Nothing"})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			moves := result.CommandsWith(ability.Move)
+			if got := len(moves) > 0; got != test.kite {
+				t.Fatalf("kited = %v, expected %v", got, test.kite)
+			}
+
+			for _, command := range moves {
+				target := point.Pt2(command.GetTargetWorldSpacePos())
+				if target.Dist(point.Pt3(enemy.Pos)) <= test.distance {
+					t.Errorf("Move target = %v, expected away from %v", target, point.Pt3(enemy.Pos))
+				}
+			}
+
+			if !test.kite && len(result.CommandsWith(ability.Attack)) == 0 {
+				t.Errorf("len(CommandsWith(Attack)) = 0, expected more than 0")
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package sim_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/buff"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+func TestRun_MedivacsHealMostDamaged(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	middle := (s.MyStart() + s.EnemyStart()) / 2
+	s.Add(api.Alliance_Self, terran.Marine, middle).Health = 30
+	damaged := s.Add(api.Alliance_Self, terran.Marine, middle+point.Pt(1, 0))
+	damaged.Health = 10
+	s.Add(api.Alliance_Self, terran.Marine, middle+point.Pt(0, 1))
+	s.Add(api.Alliance_Self, terran.Medivac, middle-point.Pt(2, 2))
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		army := b.FindArmy()
+		b.State.AttackWaves = append(b.State.AttackWaves, bot.AttackWave{Tags: army.Tags(), Target: s.EnemyStart()})
+	})
+
+	result, err := sim.Run(s.Info, s.Frames(2), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	commands := result.CommandsWith(ability.Effect_Heal)
+	if len(commands) == 0 {
+		t.Fatalf("len(CommandsWith(Effect_Heal)) = 0, expected more than 0")
+	}
+
+	for _, command := range commands {
+		if target := command.GetTargetUnitTag(); target != damaged.Tag {
+			t.Errorf("Effect_Heal target = %v, expected %v", target, damaged.Tag)
+		}
+	}
+}
+
+func TestRun_MedivacsFollowBehindBio(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	middle := (s.MyStart() + s.EnemyStart()) / 2
+	for i := 0; i < 4; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, middle+point.Pt(float64(i%2), float64(i/2)))
+	}
+	medivac := s.Add(api.Alliance_Self, terran.Medivac, middle.Towards(s.EnemyStart(), 8))
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		army := b.FindArmy()
+		b.State.AttackWaves = append(b.State.AttackWaves, bot.AttackWave{Tags: army.Tags(), Target: s.EnemyStart()})
+	})
+
+	result, err := sim.Run(s.Info, s.Frames(2), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var moves []*api.ActionRawUnitCommand
+	for _, command := range result.CommandsWith(ability.Move) {
+		if slices.Contains(command.UnitTags, medivac.Tag) {
+			moves = append(moves, command)
+		}
+	}
+
+	if len(moves) == 0 {
+		t.Fatalf("len(medivac moves) = 0, expected more than 0")
+	}
+
+	center := middle + point.Pt(0.5, 0.5)
+	for _, command := range moves {
+		target := point.Pt2(command.GetTargetWorldSpacePos())
+		if target.Dist(s.EnemyStart()) <= center.Dist(s.EnemyStart()) {
+			t.Errorf("Move target = %v, expected behind %v", target, center)
+		}
+	}
+
+	if commands := result.CommandsWith(ability.Attack); slices.ContainsFunc(commands, func(c *api.ActionRawUnitCommand) bool {
+		return slices.Contains(c.UnitTags, medivac.Tag)
+	}) {
+		t.Errorf("medivac attack-moved, expected it to follow the bio")
+	}
+}
+
+func TestRun_MedivacsBoostWhenRetreating(t *testing.T) {
+	tests := []struct {
+		name     string
+		boosting bool
+		ability  api.AbilityID
+	}{
+		{"afterburners ready", false, ability.Effect_MedivacIgniteAfterburners},
+		{"afterburners active", true, ability.Move},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := sim.NewScenario(api.Race_Zerg)
+			middle := (s.MyStart() + s.EnemyStart()) / 2
+			s.Add(api.Alliance_Self, terran.Marine, middle)
+			medivac := s.Add(api.Alliance_Self, terran.Medivac, middle)
+			if test.boosting {
+				medivac.BuffIds = []api.BuffID{buff.MedivacSpeedBoost}
+			}
+
+			strategy := sim.Setup(func(b *bot.Bot) {
+				army := b.FindArmy()
+				b.State.AttackWaves = append(b.State.AttackWaves, bot.AttackWave{Tags: army.Tags(), Target: s.EnemyStart(), State: bot.Retreating, Size: army.Len()})
+			})
+
+			result, err := sim.Run(s.Info, s.Frames(2), strategy)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			commands := result.CommandsWith(test.ability)
+			if !slices.ContainsFunc(commands, func(c *api.ActionRawUnitCommand) bool {
+				return slices.Contains(c.UnitTags, medivac.Tag)
+			}) {
+				t.Errorf("medivac commands = %v, expected %v", commands, test.ability)
+			}
+		})
+	}
+}
+
+func TestRun_MedivacsBoostAwayFromAntiAir(t *testing.T) {
+	s := sim.NewScenario(api.Race_Terran)
+	middle := (s.MyStart() + s.EnemyStart()) / 2
+	s.Add(api.Alliance_Self, terran.Marine, middle)
+	medivac := s.Add(api.Alliance_Self, terran.Medivac, middle)
+	medivac.CargoSpaceTaken = 1
+
+	// The turret can only shoot air units and it's about to be in range
+	s.Add(api.Alliance_Enemy, terran.MissileTurret, middle+point.Pt(10, 0))
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		army := b.FindArmy()
+		b.State.AttackWaves = append(b.State.AttackWaves, bot.AttackWave{Tags: army.Tags(), Target: s.EnemyStart()})
+	})
+
+	result, err := sim.Run(s.Info, s.Frames(2), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	commands := result.CommandsWith(ability.Effect_MedivacIgniteAfterburners)
+	if !slices.ContainsFunc(commands, func(c *api.ActionRawUnitCommand) bool {
+		return slices.Contains(c.UnitTags, medivac.Tag)
+	}) {
+		t.Errorf("medivac commands = %v, expected %v", commands, ability.Effect_MedivacIgniteAfterburners)
+	}
+}
+
+func TestRun_MedivacsPickUpDyingUnits(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	middle := (s.MyStart() + s.EnemyStart()) / 2
+	for i := 0; i < 6; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, middle+point.Pt(0, float64(i)))
+	}
+	dying := s.Add(api.Alliance_Self, terran.Marine, middle+point.Pt(1, 0))
+	dying.Health = 5
+	s.Add(api.Alliance_Self, terran.Medivac, middle-point.Pt(1, 0)).BuffIds = []api.BuffID{buff.MedivacSpeedBoost}
+	s.Add(api.Alliance_Enemy, zerg.Zergling, middle+point.Pt(3, 0))
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		army := b.FindArmy()
+		b.State.AttackWaves = append(b.State.AttackWaves, bot.AttackWave{Tags: army.Tags(), Target: s.EnemyStart()})
+	})
+
+	result, err := sim.Run(s.Info, s.Frames(2), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	commands := result.CommandsWith(ability.Load_Medivac)
+	if len(commands) == 0 {
+		t.Fatalf("len(CommandsWith(Load_Medivac)) = 0, expected more than 0")
+	}
+
+	for _, command := range commands {
+		if target := command.GetTargetUnitTag(); target != dying.Tag {
+			t.Errorf("Load_Medivac target = %v, expected %v", target, dying.Tag)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+func TestRun_FirstWaveWaitsForRushToBeHeld(t *testing.T) {
+	// poolSeenAt is the game loop when the spawning pool is seen.
+	const poolSeenAt = 1400
+
+	// rushWindow is the game loop when rushes are over.
+	const rushWindow = 4 * 60 * 22.4
+
+	tests := []struct {
+		name      string
+		loop      uint32
+		zerglings int
+		expected  int
+	}{
+		{name: "during the rush", loop: poolSeenAt + 100, expected: 0},
+		{name: "zerglings in the base", loop: rushWindow + 1, zerglings: 4, expected: 0},
+		{name: "held", loop: rushWindow + 1, expected: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := sim.NewScenario(api.Race_Zerg)
+			s.Loop = poolSeenAt
+			s.Add(api.Alliance_Enemy, zerg.SpawningPool, s.EnemyStart()+point.Pt(-6, 0))
+			for i := 0; i < 8; i++ {
+				s.Add(api.Alliance_Self, terran.Marine, s.MyStart()+point.Pt(6, float64(i)))
+			}
+			frames := s.Frames(2)
+
+			s.Loop = test.loop
+			for i := 0; i < test.zerglings; i++ {
+				s.Add(api.Alliance_Enemy, zerg.Zergling, s.MyStart()+point.Pt(-6, float64(i)))
+			}
+			frames = append(frames, s.Frames(2)...)
+
+			file := macro.StrategyFile{Name: "First Wave", Steps: []macro.StepFile{{Kind: "attackWave", Wave: "first"}}}
+			strategy, err := file.Strategy()
+			if err != nil {
+				t.Fatalf("Strategy() error = %v", err)
+			}
+
+			result, err := sim.Run(s.Info, frames, strategy)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			if !result.Bot.State.EnemyOpening.Opening.Aggressive() {
+				t.Fatalf("EnemyOpening = %v, expected an aggressive opening", result.Bot.State.EnemyOpening)
+			}
+
+			if got := len(result.Bot.State.AttackWaves); got != test.expected {
+				t.Errorf("len(AttackWaves) = %d, expected %d", got, test.expected)
+			}
+		})
+	}
+}
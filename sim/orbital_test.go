@@ -0,0 +1,225 @@
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/neutral"
+	"github.com/aiseeq/s2l/protocol/enums/protoss"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+)
+
+// orbitalScenario adds an orbital command with some energy at the natural.
+// When saturated, two SCVs mine every mineral field of the natural.
+func orbitalScenario(energy float32, saturated bool) (*sim.Scenario, *api.Unit) {
+	s := sim.NewScenario(api.Race_Zerg)
+
+	// The bot places its natural a bit above the simulated one
+	natural := s.MyNatural() + point.Pt(0, 1)
+	orbital := s.Add(api.Alliance_Self, terran.OrbitalCommand, natural)
+	orbital.Energy = energy
+
+	if !saturated {
+		return s, orbital
+	}
+
+	for _, mineral := range s.OfType(api.Alliance_Neutral, neutral.MineralField, neutral.MineralField750) {
+		if point.Pt3(mineral.Pos).Dist(natural) > 12 {
+			continue
+		}
+
+		for range 2 {
+			scv := s.Add(api.Alliance_Self, terran.SCV, point.Pt3(mineral.Pos).Towards(natural, 1))
+			scv.Orders = []*api.UnitOrder{{
+				AbilityId: ability.Harvest_Gather_SCV,
+				Target:    &api.UnitOrder_TargetUnitTag{TargetUnitTag: mineral.Tag},
+			}}
+		}
+	}
+
+	return s, orbital
+}
+
+// orbitalFrames starts with a frame where the orbital command has no energy,
+// so the bot knows which SCVs mine where before it can call down MULEs.
+func orbitalFrames(s *sim.Scenario, orbital *api.Unit) []*api.ResponseObservation {
+	energy := orbital.Energy
+	orbital.Energy = 0
+	frames := s.Frames(1)
+
+	orbital.Energy = energy
+	return append(frames, s.Frames(2)...)
+}
+
+func TestRun_OrbitalCallsDownMULEs(t *testing.T) {
+	tests := []struct {
+		name      string
+		saturated bool
+		expected  bool
+	}{
+		{name: "saturated", saturated: true, expected: true},
+		{name: "unsaturated", saturated: false, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, orbital := orbitalScenario(50, test.saturated)
+			var richest *api.Unit
+			for _, mineral := range s.OfType(api.Alliance_Neutral, neutral.MineralField750) {
+				if point.Pt3(mineral.Pos).Dist(point.Pt3(orbital.Pos)) < 12 {
+					richest = mineral
+				}
+			}
+			richest.MineralContents = 2000
+
+			result, err := sim.Run(s.Info, orbitalFrames(s, orbital), &bot.Strategy{Name: "Nothing"})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			commands := result.CommandsWith(ability.Effect_CalldownMULE)
+			if got := len(commands) > 0; got != test.expected {
+				t.Fatalf("MULE called down = %v, expected %v", got, test.expected)
+			}
+
+			for _, command := range commands {
+				if got := command.GetTargetUnitTag(); got != richest.Tag {
+					t.Errorf("MULE target = %v, expected %v", got, richest.Tag)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_OrbitalScansCloakedUnits(t *testing.T) {
+	s, orbital := orbitalScenario(50, true)
+	for i := 0; i < 4; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, s.MyNatural()+point.Pt(float64(i), 0))
+	}
+	templar := s.Add(api.Alliance_Enemy, protoss.DarkTemplar, s.MyNatural()+point.Pt(0, 5))
+	templar.Cloak = api.CloakState_Cloaked
+
+	result, err := sim.Run(s.Info, orbitalFrames(s, orbital), &bot.Strategy{Name: "Nothing"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	commands := result.CommandsWith(ability.Effect_Scan)
+	if len(commands) == 0 {
+		t.Fatalf("len(CommandsWith(Effect_Scan)) = 0, expected more than 0")
+	}
+
+	for _, command := range commands {
+		if got := point.Pt2(command.GetTargetWorldSpacePos()); got != point.Pt3(templar.Pos) {
+			t.Errorf("scan target = %v, expected %v", got, point.Pt3(templar.Pos))
+		}
+	}
+
+	if got := len(result.CommandsWith(ability.Effect_CalldownMULE)); got != 0 {
+		t.Errorf("len(CommandsWith(Effect_CalldownMULE)) = %d, expected %d", got, 0)
+	}
+}
+
+func TestRun_OrbitalKeepsEnergyForScans(t *testing.T) {
+	tests := []struct {
+		name     string
+		energy   float32
+		expected bool
+	}{
+		{name: "one scan", energy: 50, expected: false},
+		{name: "two scans", energy: 100, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, orbital := orbitalScenario(test.energy, true)
+			templar := s.Add(api.Alliance_Enemy, protoss.DarkTemplar, s.EnemyNatural())
+			templar.Cloak = api.CloakState_Cloaked
+
+			result, err := sim.Run(s.Info, orbitalFrames(s, orbital), &bot.Strategy{Name: "Nothing"})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			if !result.Bot.State.ScanReserved {
+				t.Errorf("ScanReserved = %v, expected %v", result.Bot.State.ScanReserved, true)
+			}
+
+			mule := len(result.CommandsWith(ability.Effect_CalldownMULE)) > 0
+			if mule != test.expected {
+				t.Errorf("MULE called down = %v, expected %v", mule, test.expected)
+			}
+		})
+	}
+}
+
+func TestRun_OrbitalDropsSupplyWhenBlocked(t *testing.T) {
+	tests := []struct {
+		name     string
+		marines  int
+		expected bool
+	}{
+		{name: "blocked", marines: 26, expected: true},
+		{name: "not blocked", marines: 25, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, orbital := orbitalScenario(50, false)
+			depot := s.Add(api.Alliance_Self, terran.SupplyDepot, s.MyStart()+point.Pt(0, 6))
+			for i := 0; i < test.marines; i++ {
+				s.Add(api.Alliance_Self, terran.Marine, s.MyStart()+point.Pt(6+float64(i/6), float64(i%6)))
+			}
+
+			result, err := sim.Run(s.Info, orbitalFrames(s, orbital), &bot.Strategy{Name: "Nothing"})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			commands := result.CommandsWith(ability.Effect_SupplyDrop)
+			if got := len(commands) > 0; got != test.expected {
+				t.Fatalf("supply dropped = %v, expected %v", got, test.expected)
+			}
+
+			for _, command := range commands {
+				if got := command.GetTargetUnitTag(); got != depot.Tag {
+					t.Errorf("supply drop target = %v, expected %v", got, depot.Tag)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_OrbitalScansForScouting(t *testing.T) {
+	s, orbital := orbitalScenario(50, true)
+	s.Loop = scoutAt
+
+	s.Fog = true
+
+	// The worker already made the first trip
+	strategy := sim.Setup(func(b *bot.Bot) {
+		b.State.Scout.Trips = 1
+	})
+
+	result, err := sim.Run(s.Info, orbitalFrames(s, orbital), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	commands := result.CommandsWith(ability.Effect_Scan)
+	if len(commands) != 1 {
+		t.Fatalf("len(CommandsWith(Effect_Scan)) = %d, expected %d", len(commands), 1)
+	}
+
+	if got := point.Pt2(commands[0].GetTargetWorldSpacePos()); got != s.EnemyStart() {
+		t.Errorf("scan target = %v, expected %v", got, s.EnemyStart())
+	}
+
+	if got := result.Bot.State.Scout.Trips; got != 2 {
+		t.Errorf("Trips = %d, expected %d", got, 2)
+	}
+}
@@ -0,0 +1,77 @@
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+)
+
+// blockedStrategy is a strategy whose first step is saving for a cost and never
+// advances. The second step counts how many times it was executed.
+func blockedStrategy(parallel bool, cost scl.Cost, executed *int) *bot.Strategy {
+	return &bot.Strategy{
+		Name:     "Blocked",
+		Parallel: parallel,
+		Steps: bot.BuildOrder{
+			{
+				Name:      "Blocked",
+				Predicate: func(b *bot.Bot) bool { return false },
+				Execute:   func(b *bot.Bot) {},
+				Next:      func(b *bot.Bot) bool { return false },
+				Cost:      func(b *bot.Bot) scl.Cost { return cost },
+			},
+			{
+				Name:      "SCV",
+				Predicate: func(b *bot.Bot) bool { return b.CanBuy(ability.Train_SCV) },
+				Execute:   func(b *bot.Bot) { *executed++ },
+				Next:      func(b *bot.Bot) bool { return true },
+			},
+		},
+	}
+}
+
+func TestRun_SequentialStopsAtBlockedStep(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	s.Minerals = 150
+
+	var executed int
+	if _, err := sim.Run(s.Info, s.Frames(2), blockedStrategy(false, scl.Cost{Minerals: 100}, &executed)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if executed != 0 {
+		t.Errorf("executed = %d, expected %d", executed, 0)
+	}
+}
+
+func TestRun_ParallelRunsStepsThatFitAfterReservation(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	s.Minerals = 150
+
+	var executed int
+	if _, err := sim.Run(s.Info, s.Frames(2), blockedStrategy(true, scl.Cost{Minerals: 100}, &executed)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if executed == 0 {
+		t.Errorf("executed = %d, expected more than 0", executed)
+	}
+}
+
+func TestRun_ParallelKeepsReservedResources(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	s.Minerals = 150
+
+	var executed int
+	if _, err := sim.Run(s.Info, s.Frames(2), blockedStrategy(true, scl.Cost{Minerals: 150}, &executed)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if executed != 0 {
+		t.Errorf("executed = %d, expected %d", executed, 0)
+	}
+}
@@ -0,0 +1,65 @@
+package sim_test
+
+import (
+	"bytes"
+	"maps"
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/agent"
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/NatoBoram/BlackCompany/record"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+func TestReplay_RecordedGameIsReproduced(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	s.Minerals = 150
+
+	server := sim.NewServer(s.Info, sim.Data(), s.Frames(4))
+	c, err := server.Connect()
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	var buffer bytes.Buffer
+	recorder := record.NewWriter(&buffer)
+	agent.Run(bot.New(c), agent.Fixed(&macro.Standard), recorder)
+	server.Close()
+
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	recording, err := record.Read(&buffer)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	result, err := sim.Replay(recording, agent.Fixed(&macro.Standard))
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	// Workers pick their mineral fields at random, so only the abilities used at
+	// each frame are compared.
+	for i := range recording.Frames {
+		recorded := abilities(recording.Actions[i])
+		if replayed := abilities(result.Server.ActionsAt(i)); !maps.Equal(recorded, replayed) {
+			t.Errorf("abilities(ActionsAt(%d)) = %v, expected %v", i, replayed, recorded)
+		}
+	}
+}
+
+// abilities counts the abilities used by unit commands.
+func abilities(actions []*api.Action) map[api.AbilityID]int {
+	counts := map[api.AbilityID]int{}
+	for _, action := range actions {
+		if command := action.GetActionRaw().GetUnitCommand(); command != nil {
+			counts[command.AbilityId]++
+		}
+	}
+
+	return counts
+}
@@ -0,0 +1,307 @@
+package sim
+
+import (
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/neutral"
+	"github.com/aiseeq/s2l/protocol/enums/protoss"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+// LoopsPerStep is how many game loops pass between two observations. It
+// matches the bot's `FramesPerOrder`.
+const LoopsPerStep = 16
+
+// Starting positions of the simulated map. Naturals are closer to their own
+// main than to the opponent's.
+var (
+	myStart      = point.Pt(24.5, 24.5)
+	myNatural    = point.Pt(24.5, 58.5)
+	enemyStart   = point.Pt(MapSize, MapSize) - myStart
+	enemyNatural = point.Pt(MapSize, MapSize) - myNatural
+)
+
+// Positions of resources relative to the town hall of a base whose minerals
+// are on its left.
+var (
+	mineralOffsets = point.Points{
+		point.Pt(-7.5, -3), point.Pt(-8.5, -2), point.Pt(-7.5, -1), point.Pt(-8.5, 0),
+		point.Pt(-7.5, 1), point.Pt(-8.5, 2), point.Pt(-7.5, 3), point.Pt(-6.5, 4),
+	}
+	geyserOffsets = point.Points{point.Pt(-4, -6), point.Pt(3, -7)}
+)
+
+// Scenario is the state of a scripted game. Tests change it between
+// observations to describe what happens in the game.
+type Scenario struct {
+	// Info is the game info sent to the bot.
+	Info *api.ResponseGameInfo
+
+	// Loop is the game loop of the next observation.
+	Loop uint32
+
+	// Minerals is the amount of minerals the bot has.
+	Minerals uint32
+
+	// Vespene is the amount of vespene gas the bot has.
+	Vespene uint32
+
+	// Upgrades are the upgrades the bot has researched.
+	Upgrades []api.UpgradeID
+
+	// Units are every unit visible by the bot.
+	Units []*api.Unit
+
+	// Fog covers the whole map with the fog of war, as if it was explored but
+	// not visible anymore.
+	Fog bool
+
+	nextTag api.UnitTag
+}
+
+// NewScenario creates the start of a game against the provided race. The bot
+// has a command center with 12 SCVs in its main and each player has a main and
+// a natural.
+func NewScenario(enemy api.Race) *Scenario {
+	s := &Scenario{Minerals: 50, nextTag: 1}
+
+	for _, base := range []point.Point{myStart, myNatural} {
+		s.addResources(base, 1)
+	}
+	for _, base := range []point.Point{enemyStart, enemyNatural} {
+		s.addResources(base, -1)
+	}
+	s.Info = gameInfo(enemy, enemyStart, s.Units)
+
+	s.Add(api.Alliance_Self, terran.CommandCenter, myStart)
+	for i := 0; i < 12; i++ {
+		offset := point.Pt(float64(i%4)-1.5, float64(i/4)-1) * 1.5
+		s.Add(api.Alliance_Self, terran.SCV, myStart-point.Pt(4, 0)+offset)
+	}
+
+	return s
+}
+
+// MyStart is the position of the bot's main base.
+func (s *Scenario) MyStart() point.Point {
+	return myStart
+}
+
+// MyNatural is the position of the bot's natural expansion.
+func (s *Scenario) MyNatural() point.Point {
+	return myNatural
+}
+
+// EnemyStart is the position of the enemy's main base.
+func (s *Scenario) EnemyStart() point.Point {
+	return enemyStart
+}
+
+// EnemyNatural is the position of the enemy's natural expansion.
+func (s *Scenario) EnemyNatural() point.Point {
+	return enemyNatural
+}
+
+// Add creates a finished unit at the provided position.
+func (s *Scenario) Add(alliance api.Alliance, unitType api.UnitTypeID, pos point.Point) *api.Unit {
+	v := unitVitalsOf(unitType)
+	data := UnitType(unitType)
+
+	unit := &api.Unit{
+		DisplayType:   api.DisplayType_Visible,
+		Alliance:      alliance,
+		Tag:           s.nextTag,
+		UnitType:      unitType,
+		Owner:         owner(alliance),
+		Pos:           &api.Point{X: float32(pos.X()), Y: float32(pos.Y()), Z: mapHeight},
+		Radius:        v.radius,
+		BuildProgress: 1,
+		Cloak:         api.CloakState_NotCloaked,
+		IsPowered:     true,
+		Health:        v.health,
+		HealthMax:     v.health,
+		Shield:        v.shield,
+		ShieldMax:     v.shield,
+		Energy:        v.energy,
+		CargoSpaceMax: v.cargo,
+		IsFlying:      isFlying(unitType),
+	}
+	if v.energy > 0 {
+		unit.EnergyMax = 200
+	}
+	if data.HasMinerals {
+		unit.MineralContents = 1800
+		if unitType == neutral.MineralField750 {
+			unit.MineralContents = 900
+		}
+	}
+	if data.HasVespene {
+		unit.VespeneContents = 2250
+	}
+
+	s.nextTag++
+	s.Units = append(s.Units, unit)
+	return unit
+}
+
+// Remove removes a unit from the game.
+func (s *Scenario) Remove(tag api.UnitTag) {
+	for i, unit := range s.Units {
+		if unit.Tag == tag {
+			s.Units = append(s.Units[:i], s.Units[i+1:]...)
+			return
+		}
+	}
+}
+
+// Unit finds a unit by its tag.
+func (s *Scenario) Unit(tag api.UnitTag) *api.Unit {
+	for _, unit := range s.Units {
+		if unit.Tag == tag {
+			return unit
+		}
+	}
+
+	return nil
+}
+
+// OfType returns the units of an alliance that are of the provided types.
+func (s *Scenario) OfType(alliance api.Alliance, types ...api.UnitTypeID) []*api.Unit {
+	var units []*api.Unit
+	for _, unit := range s.Units {
+		if unit.Alliance != alliance {
+			continue
+		}
+
+		for _, t := range types {
+			if unit.UnitType == t {
+				units = append(units, unit)
+				break
+			}
+		}
+	}
+
+	return units
+}
+
+// Observation creates an observation of the current state of the scenario.
+func (s *Scenario) Observation() *api.ResponseObservation {
+	var foodCap, foodUsed, foodWorkers, foodArmy float32
+	var armyCount uint32
+	for _, unit := range s.Units {
+		if unit.Alliance != api.Alliance_Self {
+			continue
+		}
+
+		data := UnitType(unit.UnitType)
+		if unit.BuildProgress == 1 {
+			foodCap += data.FoodProvided
+		}
+
+		foodUsed += data.FoodRequired
+		switch unit.UnitType {
+		case terran.SCV, zerg.Drone, protoss.Probe:
+			foodWorkers += data.FoodRequired
+		default:
+			if data.FoodRequired > 0 {
+				foodArmy += data.FoodRequired
+				armyCount++
+			}
+		}
+	}
+
+	observation := &api.ResponseObservation{
+		Observation: &api.Observation{
+			GameLoop: s.Loop,
+			PlayerCommon: &api.PlayerCommon{
+				PlayerId:    1,
+				Minerals:    s.Minerals,
+				Vespene:     s.Vespene,
+				FoodCap:     uint32(min(foodCap, 200)),
+				FoodUsed:    uint32(foodUsed),
+				FoodArmy:    uint32(foodArmy),
+				FoodWorkers: uint32(foodWorkers),
+				ArmyCount:   armyCount,
+			},
+			Score: &api.Score{
+				ScoreType:    api.Score_Melee,
+				ScoreDetails: &api.ScoreDetails{},
+			},
+			RawData: &api.ObservationRaw{
+				Player: &api.PlayerRaw{
+					Camera:     &api.Point{X: float32(myStart.X()), Y: float32(myStart.Y())},
+					UpgradeIds: s.Upgrades,
+				},
+				Units:    s.Units,
+				MapState: mapState(s.Fog),
+			},
+		},
+	}
+
+	// Copy the observation so that later changes to the scenario don't affect
+	// observations that were already created.
+	data, err := observation.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	copied := &api.ResponseObservation{}
+	if err := copied.Unmarshal(data); err != nil {
+		panic(err)
+	}
+
+	return copied
+}
+
+// Frames creates the provided amount of observations of the current state of
+// the scenario, advancing the game loop between each of them.
+func (s *Scenario) Frames(count int) []*api.ResponseObservation {
+	frames := make([]*api.ResponseObservation, 0, count)
+	for i := 0; i < count; i++ {
+		frames = append(frames, s.Observation())
+		s.Loop += LoopsPerStep
+	}
+
+	return frames
+}
+
+// addResources adds the mineral fields and vespene geysers of a base. The
+// direction mirrors the resources for bases of the other player.
+func (s *Scenario) addResources(base point.Point, direction float64) {
+	for i, offset := range mineralOffsets {
+		mineralField := neutral.MineralField
+		if i%2 == 1 {
+			mineralField = neutral.MineralField750
+		}
+
+		s.Add(api.Alliance_Neutral, mineralField, base+offset*point.Pt(direction, 0))
+	}
+
+	for _, offset := range geyserOffsets {
+		s.Add(api.Alliance_Neutral, neutral.VespeneGeyser, base+offset*point.Pt(direction, 0))
+	}
+}
+
+// owner returns the player that owns units of the provided alliance.
+func owner(alliance api.Alliance) api.PlayerID {
+	switch alliance {
+	case api.Alliance_Self, api.Alliance_Ally:
+		return 1
+	case api.Alliance_Enemy:
+		return 2
+	default:
+		return 16
+	}
+}
+
+// isFlying tells if a unit type is always flying.
+func isFlying(unitType api.UnitTypeID) bool {
+	switch unitType {
+	case terran.Medivac, terran.VikingFighter, terran.CommandCenterFlying, terran.OrbitalCommandFlying,
+		zerg.Mutalisk, zerg.Overlord, protoss.VoidRay:
+		return true
+	}
+
+	return false
+}
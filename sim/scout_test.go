@@ -0,0 +1,133 @@
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+// scoutAt is the game loop when the first worker scout leaves.
+const scoutAt = 1400
+
+func TestRun_WorkerScoutsEnemyStart(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	s.Loop = scoutAt
+
+	result, err := sim.Run(s.Info, s.Frames(3), &bot.Strategy{Name: "Nothing"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	scouts := map[api.UnitTag]bool{}
+	for _, command := range result.CommandsWith(ability.Move) {
+		if point.Pt2(command.GetTargetWorldSpacePos()) != s.EnemyStart() {
+			continue
+		}
+
+		for _, tag := range command.UnitTags {
+			scouts[tag] = true
+		}
+	}
+
+	if len(scouts) != 1 {
+		t.Errorf("len(scouts) = %d, expected %d", len(scouts), 1)
+	}
+}
+
+func TestRun_ScoutRetreatsWhenThreatened(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	s.Loop = scoutAt
+	frames := s.Frames(2)
+
+	// Roaches show up next to the scout once it left
+	for _, scv := range s.OfType(api.Alliance_Self, terran.SCV) {
+		s.Add(api.Alliance_Enemy, zerg.Roach, point.Pt3(scv.Pos)+point.Pt(1, 0))
+	}
+	frames = append(frames, s.Frames(2)...)
+
+	result, err := sim.Run(s.Info, frames, &bot.Strategy{Name: "Nothing"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	retreated := false
+	for _, command := range result.CommandsWith(ability.Move) {
+		if point.Pt2(command.GetTargetWorldSpacePos()) == s.MyStart() {
+			retreated = true
+		}
+	}
+
+	if !retreated {
+		t.Errorf("retreated = %v, expected %v", retreated, true)
+	}
+
+	if tag := result.Bot.State.Scout.Tag; tag != 0 {
+		t.Errorf("Scout.Tag = %v, expected %v", tag, 0)
+	}
+}
+
+func TestRun_ScoutRetreatsOutOfRange(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	s.Loop = scoutAt
+	frames := s.Frames(2)
+
+	roach := s.Add(api.Alliance_Enemy, zerg.Roach, s.MyStart()-point.Pt(4, 0))
+	frames = append(frames, s.Frames(2)...)
+
+	result, err := sim.Run(s.Info, frames, &bot.Strategy{Name: "Nothing"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var retreat, home *api.ActionRawUnitCommand
+	for _, command := range result.CommandsWith(ability.Move) {
+		switch {
+		case command.QueueCommand && point.Pt2(command.GetTargetWorldSpacePos()) == s.MyStart():
+			home = command
+		case !command.QueueCommand && point.Pt2(command.GetTargetWorldSpacePos()) != s.EnemyStart():
+			retreat = command
+		}
+	}
+
+	if retreat == nil {
+		t.Fatalf("retreat = %v, expected a move out of range", retreat)
+	}
+
+	// The roach's range, its radius and the margin kept by the influence map
+	if dist := point.Pt2(retreat.GetTargetWorldSpacePos()).Dist(point.Pt3(roach.Pos)); dist <= 4+0.625+2 {
+		t.Errorf("Dist(roach) = %v, expected more than %v", dist, 4+0.625+2)
+	}
+
+	if home == nil {
+		t.Errorf("home = %v, expected a queued move to %v", home, s.MyStart())
+	}
+}
+
+func TestRun_ScoutingFindsEnemyStart(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	s.Add(api.Alliance_Enemy, zerg.Hatchery, s.EnemyStart())
+
+	// On a 4-player map, the bot first assumes the enemy is at the first start
+	// location
+	decoys := []point.Point{point.Pt(sim.MapSize-24.5, 24.5), point.Pt(24.5, sim.MapSize-24.5)}
+	s.Info.StartRaw.StartLocations = []*api.Point2D{decoys[0].To2D(), decoys[1].To2D(), s.EnemyStart().To2D()}
+
+	result, err := sim.Run(s.Info, s.Frames(3), &bot.Strategy{Name: "Nothing"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got := result.Bot.Locs.EnemyStart; got != s.EnemyStart() {
+		t.Errorf("EnemyStart = %v, expected %v", got, s.EnemyStart())
+	}
+
+	if got := result.Bot.State.EnemyStarts; len(got) != 1 {
+		t.Errorf("len(EnemyStarts) = %d, expected %d", len(got), 1)
+	}
+}
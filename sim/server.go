@@ -0,0 +1,324 @@
+package sim
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/client"
+	"github.com/gorilla/websocket"
+)
+
+// Server is a fake StarCraft II API. It answers the bot's requests with
+// scripted observations and records the actions it receives.
+//
+// Every step request advances to the next observation. Once every observation
+// has been sent, the game ends. Starting a replay plays the same observations
+// as a replay instead.
+type Server struct {
+	info   *api.ResponseGameInfo
+	data   *api.ResponseData
+	frames []*api.ResponseObservation
+
+	mutex   sync.Mutex
+	frame   int
+	actions [][]*api.Action
+	joins   []*api.RequestJoinGame
+	replay  bool
+
+	http     *httptest.Server
+	upgrader websocket.Upgrader
+}
+
+// NewServer creates a server for the provided game, game data and
+// observations.
+func NewServer(info *api.ResponseGameInfo, data *api.ResponseData, frames []*api.ResponseObservation) *Server {
+	return &Server{
+		info:    info,
+		data:    data,
+		frames:  frames,
+		actions: make([][]*api.Action, len(frames)),
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  client.MaxMessageSize,
+			WriteBufferSize: client.MaxMessageSize,
+		},
+	}
+}
+
+// Start starts the server and returns the address where clients can connect
+// to it.
+func (s *Server) Start() (host string, port int, err error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sc2api", s.serve)
+	s.http = httptest.NewServer(mux)
+
+	host, p, err := net.SplitHostPort(s.http.Listener.Addr().String())
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to parse the server's address: %w", err)
+	}
+
+	port, err = strconv.Atoi(p)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to parse the server's port: %w", err)
+	}
+
+	return host, port, nil
+}
+
+// Connect starts the server and connects a client to it.
+func (s *Server) Connect() (*client.Client, error) {
+	host, port, err := s.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &client.Client{}
+	if err := c.TryConnect(host, port); err != nil {
+		return nil, fmt.Errorf("failed to connect to the server: %w", err)
+	}
+
+	return c, nil
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	if s.http != nil {
+		s.http.CloseClientConnections()
+		s.http.Close()
+	}
+}
+
+// Actions returns every action received by the server, in order.
+func (s *Server) Actions() []*api.Action {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var actions []*api.Action
+	for _, frame := range s.actions {
+		actions = append(actions, frame...)
+	}
+
+	return actions
+}
+
+// ActionsAt returns the actions received by the server while the bot was
+// looking at the observation at the provided index.
+func (s *Server) ActionsAt(frame int) []*api.Action {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if frame < 0 || frame >= len(s.actions) {
+		return nil
+	}
+
+	return s.actions[frame]
+}
+
+// Joins returns the requests that were received to join the game.
+func (s *Server) Joins() []*api.RequestJoinGame {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return slices.Clone(s.joins)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	for {
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		request := &api.Request{}
+		if err := request.Unmarshal(data); err != nil {
+			return
+		}
+
+		response := s.respond(request)
+		response.Id = request.Id
+
+		data, err = response.Marshal()
+		if err != nil {
+			return
+		}
+
+		if err := ws.WriteMessage(websocket.BinaryMessage, data); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) respond(request *api.Request) *api.Response {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	response := &api.Response{Status: s.status()}
+
+	switch r := request.Request.(type) {
+	case *api.Request_Ping:
+		response.Response = &api.Response_Ping{Ping: &api.ResponsePing{GameVersion: "sim"}}
+
+	case *api.Request_JoinGame:
+		s.joins = append(s.joins, r.JoinGame)
+		response.Status = api.Status_in_game
+		response.Response = &api.Response_JoinGame{JoinGame: &api.ResponseJoinGame{PlayerId: 1}}
+
+	case *api.Request_StartReplay:
+		s.replay = true
+		response.Status = s.status()
+		response.Response = &api.Response_StartReplay{StartReplay: &api.ResponseStartReplay{}}
+
+	case *api.Request_GameInfo:
+		response.Response = &api.Response_GameInfo{GameInfo: s.info}
+
+	case *api.Request_Data:
+		response.Response = &api.Response_Data{Data: s.data}
+
+	case *api.Request_Observation:
+		response.Response = &api.Response_Observation{Observation: s.observation()}
+
+	case *api.Request_Action:
+		if s.frame < len(s.actions) {
+			s.actions[s.frame] = append(s.actions[s.frame], r.Action.Actions...)
+		}
+
+		results := make([]api.ActionResult, len(r.Action.Actions))
+		for i := range results {
+			results[i] = api.ActionResult_Success
+		}
+		response.Response = &api.Response_Action{Action: &api.ResponseAction{Result: results}}
+
+	case *api.Request_Step:
+		if s.frame < len(s.frames) {
+			s.frame++
+		}
+
+		response.Status = s.status()
+		response.Response = &api.Response_Step{Step: &api.ResponseStep{SimulationLoop: s.loop()}}
+
+	case *api.Request_Query:
+		response.Response = &api.Response_Query{Query: s.query(r.Query)}
+
+	case *api.Request_Debug:
+		response.Response = &api.Response_Debug{Debug: &api.ResponseDebug{}}
+
+	case *api.Request_LeaveGame:
+		s.frame = len(s.frames)
+		response.Status = s.status()
+		response.Response = &api.Response_LeaveGame{LeaveGame: &api.ResponseLeaveGame{}}
+
+	default:
+		response.Error = []string{fmt.Sprintf("unsupported request: %T", request.Request)}
+	}
+
+	return response
+}
+
+// status tells whether the game or the replay is still running.
+func (s *Server) status() api.Status {
+	if s.frame >= len(s.frames) {
+		return api.Status_ended
+	}
+
+	if s.replay {
+		return api.Status_in_replay
+	}
+
+	return api.Status_in_game
+}
+
+// observation returns the observation at the current frame. Once the game is
+// over, the last observation is sent again.
+func (s *Server) observation() *api.ResponseObservation {
+	if len(s.frames) == 0 {
+		return &api.ResponseObservation{}
+	}
+
+	return s.frames[min(s.frame, len(s.frames)-1)]
+}
+
+func (s *Server) loop() uint32 {
+	o := s.observation()
+	if o.Observation == nil {
+		return 0
+	}
+
+	return o.Observation.GameLoop
+}
+
+// query answers pathing queries with straight distances, accepts every
+// placement inside the playable area and doesn't report any ability.
+func (s *Server) query(q *api.RequestQuery) *api.ResponseQuery {
+	response := &api.ResponseQuery{}
+
+	for _, pathing := range q.Pathing {
+		start := point.Pt2(pathing.GetStartPos())
+		if tag := pathing.GetUnitTag(); tag != 0 {
+			if unit := s.unit(tag); unit != nil {
+				start = point.Pt3(unit.Pos)
+			}
+		}
+
+		end := point.Pt2(pathing.EndPos)
+		response.Pathing = append(response.Pathing, &api.ResponseQueryPathing{
+			Distance: float32(start.Dist(end)),
+		})
+	}
+
+	for _, ability := range q.Abilities {
+		response.Abilities = append(response.Abilities, &api.ResponseQueryAvailableAbilities{
+			UnitTag:    ability.UnitTag,
+			UnitTypeId: s.unitType(ability.UnitTag),
+			Abilities:  availableAbilities(s.unit(ability.UnitTag)),
+		})
+	}
+
+	area := s.info.StartRaw.PlayableArea
+	for _, placement := range q.Placements {
+		result := api.ActionResult_Success
+		pos := placement.TargetPos
+		if pos == nil || pos.X < float32(area.P0.X) || pos.Y < float32(area.P0.Y) ||
+			pos.X > float32(area.P1.X) || pos.Y > float32(area.P1.Y) {
+			result = api.ActionResult_CantBuildLocationInvalid
+		}
+
+		response.Placements = append(response.Placements, &api.ResponseQueryBuildingPlacement{Result: result})
+	}
+
+	return response
+}
+
+func (s *Server) unit(tag api.UnitTag) *api.Unit {
+	o := s.observation()
+	if o.Observation == nil || o.Observation.RawData == nil {
+		return nil
+	}
+
+	for _, unit := range o.Observation.RawData.Units {
+		if unit.Tag == tag {
+			return unit
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) unitType(tag api.UnitTag) api.UnitTypeID {
+	if unit := s.unit(tag); unit != nil {
+		return unit.UnitType
+	}
+
+	return 0
+}
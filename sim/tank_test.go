@@ -0,0 +1,102 @@
+package sim_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/lib/point"
+	"github.com/aiseeq/s2l/lib/scl"
+	"github.com/aiseeq/s2l/protocol/api"
+	"github.com/aiseeq/s2l/protocol/enums/ability"
+	"github.com/aiseeq/s2l/protocol/enums/terran"
+	"github.com/aiseeq/s2l/protocol/enums/zerg"
+)
+
+func TestRun_TanksSiegeWhenEnemiesApproach(t *testing.T) {
+	tests := []struct {
+		name     string
+		tank     api.UnitTypeID
+		distance float64
+		ability  api.AbilityID
+	}{
+		{"mobile tank with enemies approaching", terran.SiegeTank, 14, ability.Morph_SiegeMode},
+		{"mobile tank without enemies", terran.SiegeTank, 40, ability.Attack},
+		{"sieged tank with enemies in range", terran.SiegeTankSieged, 10, 0},
+		{"sieged tank without enemies", terran.SiegeTankSieged, 40, ability.Morph_Unsiege},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := sim.NewScenario(api.Race_Zerg)
+			middle := (s.MyStart() + s.EnemyStart()) / 2
+			tank := s.Add(api.Alliance_Self, test.tank, middle)
+			s.Add(api.Alliance_Enemy, zerg.Zergling, middle.Towards(s.EnemyStart(), test.distance))
+
+			strategy := sim.Setup(func(b *bot.Bot) {
+				army := b.FindArmy()
+				b.State.AttackWaves = append(b.State.AttackWaves, bot.AttackWave{Tags: army.Tags(), Target: s.EnemyStart()})
+			})
+
+			result, err := sim.Run(s.Info, s.Frames(2), strategy)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			var abilities []api.AbilityID
+			for _, command := range result.Commands() {
+				if slices.Contains(command.UnitTags, tank.Tag) {
+					abilities = append(abilities, command.AbilityId)
+				}
+			}
+
+			if test.ability == 0 {
+				if len(abilities) != 0 {
+					t.Errorf("tank abilities = %v, expected none", abilities)
+				}
+				return
+			}
+
+			if !slices.Contains(abilities, test.ability) {
+				t.Errorf("tank abilities = %v, expected %v", abilities, test.ability)
+			}
+		})
+	}
+}
+
+func TestRun_TanksLeapfrog(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	middle := (s.MyStart() + s.EnemyStart()) / 2
+	for i := 0; i < 6; i++ {
+		s.Add(api.Alliance_Self, terran.Marine, middle+point.Pt(float64(i%2), float64(i/2)))
+	}
+	front := s.Add(api.Alliance_Self, terran.SiegeTankSieged, middle.Towards(s.EnemyStart(), -5))
+	rear := s.Add(api.Alliance_Self, terran.SiegeTankSieged, middle.Towards(s.EnemyStart(), -9))
+	s.Add(api.Alliance_Enemy, zerg.Zergling, middle.Towards(s.EnemyStart(), 11))
+
+	strategy := sim.Setup(func(b *bot.Bot) {
+		army := b.FindArmy()
+		b.State.AttackWaves = append(b.State.AttackWaves, bot.AttackWave{Tags: army.Tags(), Target: s.EnemyStart()})
+	})
+
+	result, err := sim.Run(s.Info, s.Frames(1), strategy)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var unsieged scl.Tags
+	for _, command := range result.CommandsWith(ability.Morph_Unsiege) {
+		unsieged = append(unsieged, command.UnitTags...)
+	}
+
+	if !slices.Equal(unsieged, scl.Tags{rear.Tag}) {
+		t.Errorf("unsieged = %v, expected %v", unsieged, scl.Tags{rear.Tag})
+	}
+
+	for _, command := range result.Commands() {
+		if slices.Contains(command.UnitTags, front.Tag) {
+			t.Errorf("front tank ability = %v, expected it to stay sieged", command.AbilityId)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+func TestRun_TraceRecordsSupplyDepot(t *testing.T) {
+	s := sim.NewScenario(api.Race_Zerg)
+	s.Minerals = 150
+
+	result, err := sim.Run(s.Info, s.Frames(4), &macro.Standard)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	step := result.Game.Trace.Steps[1]
+	if step.Ready == nil {
+		t.Fatalf("Steps[1].Ready = %v, expected a moment", step.Ready)
+	}
+
+	if step.Executed == nil {
+		t.Errorf("Steps[1].Executed = %v, expected a moment", step.Executed)
+	}
+
+	if step.Ready.Supply != 12 {
+		t.Errorf("Steps[1].Ready.Supply = %d, expected %d", step.Ready.Supply, 12)
+	}
+
+	if step.Done != nil {
+		t.Errorf("Steps[1].Done = %v, expected %v", step.Done, nil)
+	}
+}
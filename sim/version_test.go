@@ -0,0 +1,36 @@
+package sim_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NatoBoram/BlackCompany/macro"
+	"github.com/NatoBoram/BlackCompany/sim"
+	"github.com/aiseeq/s2l/protocol/api"
+)
+
+func TestRun_EachGameAnnouncesVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "announce.yaml")
+	if err := os.WriteFile(path, []byte("name: Announce\nsteps:\n  - kind: chatVersion\n"), 0o644); err != nil {
+		t.Fatalf("failed to write %q: %v", path, err)
+	}
+
+	strategy, err := macro.LoadStrategy(path)
+	if err != nil {
+		t.Fatalf("LoadStrategy() error = %v", err)
+	}
+
+	for game := 1; game <= 2; game++ {
+		s := sim.NewScenario(api.Race_Zerg)
+
+		result, err := sim.Run(s.Info, s.Frames(2), strategy)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if chat := result.Chat(); len(chat) != 1 {
+			t.Errorf("len(Chat()) = %d in game %d, expected %d", len(chat), game, 1)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NatoBoram/BlackCompany/agent"
+	"github.com/NatoBoram/BlackCompany/bot"
+	"github.com/NatoBoram/BlackCompany/log"
+	"github.com/NatoBoram/BlackCompany/macro"
+)
+
+// listStrategies prints the strategies that can be selected with `-strategy`.
+func listStrategies() {
+	for _, strategy := range macro.Strategies {
+		races := "any race"
+		if len(strategy.EnemyRaces) > 0 {
+			names := make([]string, 0, len(strategy.EnemyRaces))
+			for _, race := range strategy.EnemyRaces {
+				names = append(names, race.String())
+			}
+			races = strings.Join(names, ", ")
+		}
+
+		fmt.Printf("%s\tagainst %s\n", strategy.Name, races)
+	}
+
+	fmt.Printf("%s\tpicks a strategy meant for the enemy's race, or for any race against Random\n", macro.RandomStrategy)
+}
+
+// loadStrategyFile loads a strategy from a file and makes it selectable by
+// name.
+func loadStrategyFile(path string) (*bot.Strategy, error) {
+	strategy, err := macro.LoadStrategy(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := macro.RegisterStrategy(strategy); err != nil {
+		return nil, fmt.Errorf("failed to register strategy %q: %w", strategy.Name, err)
+	}
+
+	log.Info("Loaded strategy %s from %q", strategy.Name, path)
+	return strategy, nil
+}
+
+// strategist chooses the strategy selected with `-strategy` once the enemy's
+// race is known.
+func strategist(name string) agent.Strategist {
+	return func(b *bot.Bot) *bot.Strategy {
+		strategy, err := macro.ChooseStrategy(name, b.EnemyRace)
+		if err != nil {
+			log.Warn("Failed to choose a strategy, playing %s instead: %v", macro.Standard.Name, err)
+			return &macro.Standard
+		}
+
+		if !strategy.IsMeantFor(b.EnemyRace) {
+			log.Warn("%s isn't meant to play against %v", strategy.Name, b.EnemyRace)
+		}
+
+		return strategy
+	}
+}